@@ -0,0 +1,237 @@
+package github
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGithubBranchProtectionRules() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_ID: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// Computed
+			PROTECTION_RULES: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						PROTECTION_RULE_ID: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						PROTECTION_PATTERN: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						PROTECTION_MATCHING_REFS: {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The branches the pattern currently applies to.",
+						},
+						PROTECTION_IS_ADMIN_ENFORCED: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_REQUIRES_COMMIT_SIGNATURES: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_REQUIRES_LINEAR_HISTORY: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_REQUIRES_CONVERSATION_RESOLUTION: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_ALLOWS_DELETIONS: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_ALLOWS_FORCE_PUSHES: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_BLOCKS_CREATIONS: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_LOCK_BRANCH: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						PROTECTION_REQUIRES_APPROVING_REVIEWS: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT: {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									PROTECTION_REQUIRES_CODE_OWNER_REVIEWS: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									PROTECTION_REQUIRE_LAST_PUSH_APPROVAL: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									PROTECTION_DISMISSES_STALE_REVIEWS: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									PROTECTION_RESTRICT_DISMISSALS: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									PROTECTION_REVIEW_DISMISSAL_ALLOWANCES: {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     branchProtectionActorDataResource(),
+									},
+									PROTECTION_BYPASS_PULL_REQUEST_ALLOWANCES: {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     branchProtectionActorDataResource(),
+									},
+								},
+							},
+						},
+						PROTECTION_REQUIRES_STATUS_CHECKS: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									PROTECTION_REQUIRES_STRICT_STATUS_CHECKS: {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									PROTECTION_REQUIRED_STATUS_CHECKS: {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												PROTECTION_STATUS_CHECK_CONTEXT: {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												PROTECTION_STATUS_CHECK_APP_ID: {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						PROTECTION_PUSH_ALLOWANCES: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     branchProtectionActorDataResource(),
+						},
+						PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     branchProtectionActorDataResource(),
+						},
+					},
+				},
+			},
+		},
+
+		Read: dataSourceGithubBranchProtectionRulesRead,
+	}
+}
+
+// branchProtectionActorDataResource mirrors branchProtectionActorResource
+// with every attribute computed.
+func branchProtectionActorDataResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			ACTOR_APP: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ACTOR_TEAM: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ACTOR_USER: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ACTOR_ID: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ACTOR_TYPE: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceGithubBranchProtectionRulesRead(d *schema.ResourceData, meta interface{}) error {
+	repositoryID := d.Get(REPOSITORY_ID).(string)
+	ctx := meta.(*Organization).StopContext
+
+	ids, err := getBranchProtectionRuleIDs(ctx, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		protection, err := getBranchProtectionRule(ctx, id, meta)
+		if err != nil {
+			return err
+		}
+
+		refs, err := getBranchProtectionMatchingRefs(ctx, id, meta)
+		if err != nil {
+			return err
+		}
+
+		rules = append(rules, map[string]interface{}{
+			PROTECTION_RULE_ID:                          id,
+			PROTECTION_PATTERN:                          string(protection.Pattern),
+			PROTECTION_MATCHING_REFS:                    refs,
+			PROTECTION_IS_ADMIN_ENFORCED:                bool(protection.IsAdminEnforced),
+			PROTECTION_REQUIRES_COMMIT_SIGNATURES:       bool(protection.RequiresCommitSignatures),
+			PROTECTION_REQUIRES_LINEAR_HISTORY:          bool(protection.RequiresLinearHistory),
+			PROTECTION_REQUIRES_CONVERSATION_RESOLUTION: bool(protection.RequiresConversationResolution),
+			PROTECTION_ALLOWS_DELETIONS:                 bool(protection.AllowsDeletions),
+			PROTECTION_ALLOWS_FORCE_PUSHES:              bool(protection.AllowsForcePushes),
+			PROTECTION_BLOCKS_CREATIONS:                 bool(protection.BlocksCreations),
+			PROTECTION_LOCK_BRANCH:                      bool(protection.LockBranch),
+			PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE:      bool(protection.LockAllowsFetchAndMerge),
+			PROTECTION_REQUIRES_APPROVING_REVIEWS:       setApprovingReviews(protection),
+			PROTECTION_REQUIRES_STATUS_CHECKS:           setStatusChecks(protection),
+			PROTECTION_PUSH_ALLOWANCES:                  setPushes(protection),
+			PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES:     flattenAllowances(protection.BypassForcePushAllowances.Nodes),
+		})
+	}
+
+	err = d.Set(PROTECTION_RULES, rules)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/branch_protection_rules", repositoryID))
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
@@ -17,68 +16,192 @@ func dataSourceGithubCodeowners() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			CODEOWNERS_REF: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The branch, tag or commit to inspect; defaults to the default branch.",
+			},
+			CODEOWNERS_VALIDATE_OWNERS: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Check each owner is a user or team with write access to the repository.",
+			},
 			// Computed
 			CODEOWNERS_EXISTS: {
 				Type:     schema.TypeBool,
 				Computed: true,
 			},
+			CODEOWNERS_PATH: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			CODEOWNERS_RULES: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						CODEOWNERS_LINE: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						CODEOWNERS_OWNERS: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						CODEOWNERS_PATTERN: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			CODEOWNERS_INVALID_OWNERS: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						CODEOWNERS_LINE: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						CODEOWNERS_OWNER: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						CODEOWNERS_REASON: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 
 		Read: resourceGithubAppInitCodeownersRead,
 	}
 }
 
+type codeownersBlob struct {
+	Blob struct {
+		ID   githubv4.ID
+		Text githubv4.String
+	} `graphql:"... on Blob"`
+}
+
 func resourceGithubAppInitCodeownersRead(d *schema.ResourceData, meta interface{}) error {
 	var query struct {
 		Node struct {
 			Repository struct {
-				Root struct {
-					GitObject struct {
-						ID githubv4.ID
-					} `graphql:"... on Blob"`
-				} `graphql:"root:object(expression: $rootExpression)"`
-				Github struct {
-					GitObject struct {
-						ID githubv4.ID
-					} `graphql:"... on Blob"`
-				} `graphql:"github:object(expression: $githubExpression)"`
-				Docs struct {
-					GitObject struct {
-						ID githubv4.ID
-					} `graphql:"... on Blob"`
-				} `graphql:"docs:object(expression: $docsExpression)"`
+				NameWithOwner githubv4.String
+				Github        codeownersBlob `graphql:"github:object(expression: $githubExpression)"`
+				Root          codeownersBlob `graphql:"root:object(expression: $rootExpression)"`
+				Docs          codeownersBlob `graphql:"docs:object(expression: $docsExpression)"`
 			} `graphql:"... on Repository"`
 		} `graphql:"node(id: $id)"`
 	}
-	variables := map[string]interface{}{
-		"id":               githubv4.ID(d.Get(REPOSITORY_ID).(string)),
-		"rootExpression":   githubv4.String("master:CODEOWNERS"),
-		"githubExpression": githubv4.String("master:.github/CODEOWNERS"),
-		"docsExpression":   githubv4.String("master:docs/CODEOWNERS"),
-	}
 
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
-	err := client.Query(ctx, &query, variables)
-	if err != nil {
-		return err
-	}
 
-	root := query.Node.Repository.Root.GitObject.ID
-	github := query.Node.Repository.Github.GitObject.ID
-	docs := query.Node.Repository.Docs.GitObject.ID
-	if root != nil || github != nil || docs != nil {
-		err = d.Set(CODEOWNERS_EXISTS, true)
+	ref := d.Get(CODEOWNERS_REF).(string)
+	if ref == "" {
+		var err error
+		ref, err = getRepositoryDefaultBranch(ctx, d.Get(REPOSITORY_ID).(string), meta)
 		if err != nil {
 			return err
 		}
-	} else {
-		err = d.Set(CODEOWNERS_EXISTS, false)
+	}
+
+	// An empty repository has no default branch, and so no CODEOWNERS.
+	if ref != "" {
+		variables := map[string]interface{}{
+			"id":               githubv4.ID(d.Get(REPOSITORY_ID).(string)),
+			"githubExpression": githubv4.String(fmt.Sprintf("%s:%s", ref, CODEOWNERS_PATHS[0])),
+			"rootExpression":   githubv4.String(fmt.Sprintf("%s:%s", ref, CODEOWNERS_PATHS[1])),
+			"docsExpression":   githubv4.String(fmt.Sprintf("%s:%s", ref, CODEOWNERS_PATHS[2])),
+		}
+		err := client.Query(ctx, &query, variables)
 		if err != nil {
 			return err
 		}
 	}
 
+	repository := query.Node.Repository
+	path, text := "", ""
+	for i, b := range []codeownersBlob{repository.Github, repository.Root, repository.Docs} {
+		if b.Blob.ID != nil {
+			path, text = CODEOWNERS_PATHS[i], string(b.Blob.Text)
+			break
+		}
+	}
+
+	rules := parseCodeowners(text)
+
+	var allRules []map[string]interface{}
+	var invalidOwners []map[string]interface{}
+	reasons := make(map[string]string)
+	for _, r := range rules {
+		owners := make([]interface{}, 0, len(r.Owners))
+		for _, o := range r.Owners {
+			owners = append(owners, o)
+		}
+		allRules = append(allRules, map[string]interface{}{
+			CODEOWNERS_LINE:    r.Line,
+			CODEOWNERS_OWNERS:  owners,
+			CODEOWNERS_PATTERN: r.Pattern,
+		})
+
+		if !d.Get(CODEOWNERS_VALIDATE_OWNERS).(bool) {
+			continue
+		}
+		for _, o := range r.Owners {
+			reason, ok := reasons[o]
+			if !ok {
+				var err error
+				reason, err = validateCodeowner(ctx, string(repository.NameWithOwner), o, meta)
+				if err != nil {
+					return err
+				}
+				reasons[o] = reason
+			}
+			if reason != "" {
+				invalidOwners = append(invalidOwners, map[string]interface{}{
+					CODEOWNERS_LINE:   r.Line,
+					CODEOWNERS_OWNER:  o,
+					CODEOWNERS_REASON: reason,
+				})
+			}
+		}
+	}
+
+	err := d.Set(CODEOWNERS_EXISTS, path != "")
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(CODEOWNERS_PATH, path)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(CODEOWNERS_REF, ref)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(CODEOWNERS_RULES, allRules)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(CODEOWNERS_INVALID_OWNERS, invalidOwners)
+	if err != nil {
+		return err
+	}
+
 	d.SetId(fmt.Sprintf("%s/codeowners", d.Get(REPOSITORY_ID).(string)))
 
 	return nil
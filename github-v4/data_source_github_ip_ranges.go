@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
 )
@@ -56,7 +55,7 @@ func resourceGithubAppInitIpRangesRead(d *schema.ResourceData, meta interface{})
 	}
 	variables := map[string]interface{}{}
 
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 	err := client.Query(ctx, &query, variables)
 	if err != nil {
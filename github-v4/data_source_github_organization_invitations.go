@@ -0,0 +1,112 @@
+package github
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGithubOrganizationInvitations() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Computed
+			ORGANIZATION_INVITATIONS: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     organizationInvitationResource(),
+			},
+			ORGANIZATION_FAILED_INVITATIONS: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     organizationInvitationResource(),
+			},
+		},
+
+		Read: dataSourceGithubOrganizationInvitationsRead,
+	}
+}
+
+func organizationInvitationResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			INVITATION_ID: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			INVITATION_CREATED_AT: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			INVITATION_EMAIL: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			INVITATION_FAILED_AT: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			INVITATION_FAILED_REASON: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			INVITATION_INVITER: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			USER_LOGIN: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			USER_ROLE: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceGithubOrganizationInvitationsRead(d *schema.ResourceData, meta interface{}) error {
+	ctx := meta.(*Organization).StopContext
+
+	pending, err := getOrganizationInvitations(ctx, false, meta)
+	if err != nil {
+		return err
+	}
+
+	failed, err := getOrganizationInvitations(ctx, true, meta)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(ORGANIZATION_INVITATIONS, flattenOrganizationInvitations(pending))
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(ORGANIZATION_FAILED_INVITATIONS, flattenOrganizationInvitations(failed))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/invitations", meta.(*Organization).Name))
+
+	return nil
+}
+
+func flattenOrganizationInvitations(invitations []OrganizationInvitation) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, i := range invitations {
+		invitation := make(map[string]interface{})
+		invitation[INVITATION_ID] = i.ID
+		invitation[INVITATION_CREATED_AT] = i.CreatedAt
+		invitation[INVITATION_EMAIL] = i.Email
+		invitation[INVITATION_FAILED_AT] = i.FailedAt
+		invitation[INVITATION_FAILED_REASON] = i.FailedReason
+		invitation[INVITATION_INVITER] = i.Inviter.Login
+		invitation[USER_LOGIN] = i.Login
+		invitation[USER_ROLE] = organizationRole(i.Role)
+		out = append(out, invitation)
+	}
+	return out
+}
@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
@@ -66,7 +65,7 @@ func resourceGithubAppInitOrganizationMembersRead(d *schema.ResourceData, meta i
 		Node User
 		Role githubv4.OrganizationMemberRole
 	}
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 	for {
 		err := client.Query(ctx, &query, variables)
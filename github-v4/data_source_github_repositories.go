@@ -1,11 +1,12 @@
 package github
 
 import (
-	"context"
 	"fmt"
-
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/shurcooL/githubv4"
+	"regexp"
+	"strings"
 )
 
 func dataSourceGithubRepositories() *schema.Resource {
@@ -13,21 +14,51 @@ func dataSourceGithubRepositories() *schema.Resource {
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_VISIBILITY: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(githubv4.RepositoryVisibilityInternal),
+					string(githubv4.RepositoryVisibilityPrivate),
+					string(githubv4.RepositoryVisibilityPublic),
+				}, false),
+			},
+			REPOSITORIES_INCLUDE_ARCHIVED: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORIES_INCLUDE_FORKS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_IS_TEMPLATE: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return template repositories when true, or non-template ones when false.",
+			},
+			REPOSITORIES_TOPIC: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			REPOSITORIES_NAME_REGEX: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+			},
+			REPOSITORIES_LANGUAGE: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The primary language of the repositories, compared case-insensitively.",
+			},
 			// Computed
 			ORGANIZATION_REPOSITORIES: {
 				Type:     schema.TypeList,
 				Computed: true,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						REPOSITORY_ID: {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						REPOSITORY_NAME: {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-					},
+					Schema: repositoryDetailsSchema(),
 				},
 			},
 		},
@@ -40,34 +71,73 @@ func dataSourceGithubRepositoriesRead(d *schema.ResourceData, meta interface{})
 	var query struct {
 		Organization struct {
 			Repositories struct {
-				Nodes []struct {
-					ID   githubv4.ID
-					Name githubv4.String
-				}
+				Nodes    []RepositoryDetails
 				PageInfo PageInfo
-			} `graphql:"repositories(first: $first, after: $cursor)"`
+			} `graphql:"repositories(first: $first, after: $cursor, privacy: $privacy, isFork: $isFork, isArchived: $isArchived)"`
 		} `graphql:"organization(login: $login)"`
 	}
 	variables := map[string]interface{}{
-		"login":  githubv4.String(meta.(*Organization).Name),
-		"first":  githubv4.Int(100),
-		"cursor": (*githubv4.String)(nil),
+		"login":      githubv4.String(meta.(*Organization).Name),
+		"first":      githubv4.Int(100),
+		"cursor":     (*githubv4.String)(nil),
+		"privacy":    (*githubv4.RepositoryPrivacy)(nil),
+		"isFork":     (*githubv4.Boolean)(nil),
+		"isArchived": (*githubv4.Boolean)(nil),
 	}
 
-	var allRepositories []struct {
-		ID   githubv4.ID
-		Name githubv4.String
+	// Filter server-side where the connection allows it. Privacy has no
+	// INTERNAL value, so internal repositories are fetched as private and
+	// told apart by their visibility below.
+	visibility := d.Get(REPOSITORY_VISIBILITY).(string)
+	switch visibility {
+	case string(githubv4.RepositoryVisibilityPublic):
+		variables["privacy"] = githubv4.RepositoryPrivacyPublic
+	case string(githubv4.RepositoryVisibilityPrivate), string(githubv4.RepositoryVisibilityInternal):
+		variables["privacy"] = githubv4.RepositoryPrivacyPrivate
+	}
+	if !d.Get(REPOSITORIES_INCLUDE_FORKS).(bool) {
+		variables["isFork"] = githubv4.NewBoolean(false)
+	}
+	if !d.Get(REPOSITORIES_INCLUDE_ARCHIVED).(bool) {
+		variables["isArchived"] = githubv4.NewBoolean(false)
 	}
 
-	ctx := context.Background()
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk(REPOSITORIES_NAME_REGEX); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	topic := d.Get(REPOSITORIES_TOPIC).(string)
+	language := d.Get(REPOSITORIES_LANGUAGE).(string)
+	isTemplate, filterTemplate := d.GetOkExists(REPOSITORY_IS_TEMPLATE)
+
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
+
+	var repositories []map[string]interface{}
 	for {
 		err := client.Query(ctx, &query, variables)
 		if err != nil {
 			return err
 		}
 
-		allRepositories = append(allRepositories, query.Organization.Repositories.Nodes...)
+		for _, r := range query.Organization.Repositories.Nodes {
+			if visibility != "" && string(r.Visibility) != visibility {
+				continue
+			}
+			if filterTemplate && bool(r.IsTemplate) != isTemplate.(bool) {
+				continue
+			}
+			if nameRegex != nil && !nameRegex.MatchString(string(r.Name)) {
+				continue
+			}
+			if language != "" && !strings.EqualFold(string(r.PrimaryLanguage.Name), language) {
+				continue
+			}
+			if topic != "" && !repositoryHasTopic(r, topic) {
+				continue
+			}
+			repositories = append(repositories, flattenRepositoryDetails(r))
+		}
 
 		if !query.Organization.Repositories.PageInfo.HasNextPage {
 			break
@@ -76,13 +146,6 @@ func dataSourceGithubRepositoriesRead(d *schema.ResourceData, meta interface{})
 		variables["cursor"] = githubv4.NewString(query.Organization.Repositories.PageInfo.EndCursor)
 	}
 
-	var repositories []map[string]interface{}
-	for _, r := range allRepositories {
-		repository := make(map[string]interface{})
-		repository[REPOSITORY_ID] = fmt.Sprintf("%s", r.ID)
-		repository[REPOSITORY_NAME] = string(r.Name)
-		repositories = append(repositories, repository)
-	}
 	err := d.Set(ORGANIZATION_REPOSITORIES, repositories)
 	if err != nil {
 		return err
@@ -92,3 +155,12 @@ func dataSourceGithubRepositoriesRead(d *schema.ResourceData, meta interface{})
 
 	return nil
 }
+
+func repositoryHasTopic(r RepositoryDetails, topic string) bool {
+	for _, t := range r.RepositoryTopics.Nodes {
+		if strings.EqualFold(string(t.Topic.Name), topic) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,24 +1,25 @@
 package github
 
 import (
-	"context"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
+	"log"
 )
 
 func dataSourceGithubRepository() *schema.Resource {
+	s := repositoryDetailsSchema()
+	// Input
+	s[REPOSITORY_NAME] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "",
+	}
+
 	return &schema.Resource{
 		SchemaVersion: 1,
 
-		Schema: map[string]*schema.Schema{
-			// Input
-			REPOSITORY_NAME: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "",
-			},
-		},
+		Schema: s,
 
 		Read: dataSourceGithubRepositoryRead,
 	}
@@ -26,16 +27,14 @@ func dataSourceGithubRepository() *schema.Resource {
 
 func dataSourceGithubRepositoryRead(d *schema.ResourceData, meta interface{}) error {
 	var query struct {
-		Repository struct {
-			ID githubv4.ID
-		} `graphql:"repository(owner:$owner, name:$name)"`
+		Repository RepositoryDetails `graphql:"repository(owner:$owner, name:$name)"`
 	}
 	variables := map[string]interface{}{
 		"owner": githubv4.String(meta.(*Organization).Name),
-		"name":  githubv4.String(d.Get("name").(string)),
+		"name":  githubv4.String(d.Get(REPOSITORY_NAME).(string)),
 	}
 
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 	err := client.Query(ctx, &query, variables)
 	if err != nil {
@@ -44,5 +43,12 @@ func dataSourceGithubRepositoryRead(d *schema.ResourceData, meta interface{}) er
 
 	d.SetId(fmt.Sprintf("%s", query.Repository.ID))
 
+	for k, v := range flattenRepositoryDetails(query.Repository) {
+		err = d.Set(k, v)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in %s repository (%s)", k, query.Repository.Name, d.Id())
+		}
+	}
+
 	return nil
 }
@@ -1,9 +1,9 @@
 package github
 
 import (
-	"context"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/shurcooL/githubv4"
 )
 
@@ -18,6 +18,16 @@ func dataSourceGithubRepositoryCollaborators() *schema.Resource {
 				Required:    true,
 				Description: "",
 			},
+			COLLABORATOR_AFFILIATION: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(githubv4.CollaboratorAffiliationAll),
+				ValidateFunc: validation.StringInSlice([]string{
+					string(githubv4.CollaboratorAffiliationAll),
+					string(githubv4.CollaboratorAffiliationDirect),
+					string(githubv4.CollaboratorAffiliationOutside),
+				}, false),
+			},
 			// Computed
 			REPOSITORY_COLLABORATORS: {
 				Type:     schema.TypeList,
@@ -40,6 +50,30 @@ func dataSourceGithubRepositoryCollaborators() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						PERMISSION_SOURCES: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									USER_PERMISSION: {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									PERMISSION_SOURCE_ID: {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									PERMISSION_SOURCE_NAME: {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									PERMISSION_SOURCE_TYPE: {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -55,27 +89,30 @@ func dataSourceGithubRepositoryCollaboratorsRead(d *schema.ResourceData, meta in
 			Repository struct {
 				Collaborators struct {
 					Edges []struct {
-						Node       User
-						Permission githubv4.RepositoryPermission
+						Node              User
+						Permission        githubv4.RepositoryPermission
+						PermissionSources []PermissionSource
 					}
 					PageInfo PageInfo
-				} `graphql:"collaborators(first: $first, after: $cursor)"`
+				} `graphql:"collaborators(first: $first, after: $cursor, affiliation: $affiliation)"`
 				ID githubv4.ID
 			} `graphql:"... on Repository"`
 		} `graphql:"node(id: $id)"`
 	}
 	variables := map[string]interface{}{
-		"id":     githubv4.ID(d.Get(REPOSITORY_ID).(string)),
-		"first":  githubv4.Int(100),
-		"cursor": (*githubv4.String)(nil),
+		"id":          githubv4.ID(d.Get(REPOSITORY_ID).(string)),
+		"affiliation": githubv4.CollaboratorAffiliation(d.Get(COLLABORATOR_AFFILIATION).(string)),
+		"first":       githubv4.Int(100),
+		"cursor":      (*githubv4.String)(nil),
 	}
 
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 
 	var allEdges []struct {
-		Node       User
-		Permission githubv4.RepositoryPermission
+		Node              User
+		Permission        githubv4.RepositoryPermission
+		PermissionSources []PermissionSource
 	}
 	for {
 		err := client.Query(ctx, &query, variables)
@@ -98,6 +135,7 @@ func dataSourceGithubRepositoryCollaboratorsRead(d *schema.ResourceData, meta in
 		user[USER_LOGIN] = string(u.Node.Login)
 		user[USER_NAME] = string(u.Node.Name)
 		user[USER_PERMISSION] = string(u.Permission)
+		user[PERMISSION_SOURCES] = flattenPermissionSources(u.PermissionSources)
 		allUsers = append(allUsers, user)
 	}
 
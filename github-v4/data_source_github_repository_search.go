@@ -0,0 +1,118 @@
+package github
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/shurcooL/githubv4"
+	"regexp"
+)
+
+func dataSourceGithubRepositorySearch() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			SEARCH_QUERY: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "A GitHub search query, scoped to the organization unless it names an org, user or repo.",
+			},
+			SEARCH_ALLOW_TRUNCATED: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Return the first 1000 results rather than failing when more repositories match.",
+			},
+			// Computed
+			ORGANIZATION_REPOSITORIES: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: repositoryDetailsSchema(),
+				},
+			},
+			SEARCH_TOTAL_COUNT: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			SEARCH_TRUNCATED: {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+
+		Read: dataSourceGithubRepositorySearchRead,
+	}
+}
+
+var searchOwnerQualifier = regexp.MustCompile(`(^|\s)(org|user|repo):\S`)
+
+func dataSourceGithubRepositorySearchRead(d *schema.ResourceData, meta interface{}) error {
+	var query struct {
+		Search struct {
+			Nodes []struct {
+				Repository RepositoryDetails `graphql:"... on Repository"`
+			}
+			PageInfo        PageInfo
+			RepositoryCount githubv4.Int
+		} `graphql:"search(type: REPOSITORY, query: $query, first: $first, after: $cursor)"`
+	}
+
+	q := d.Get(SEARCH_QUERY).(string)
+	if !searchOwnerQualifier.MatchString(q) {
+		q = fmt.Sprintf("org:%s %s", meta.(*Organization).Name, q)
+	}
+	variables := map[string]interface{}{
+		"query":  githubv4.String(q),
+		"first":  githubv4.Int(100),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+
+	var repositories []map[string]interface{}
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return err
+		}
+
+		// Search silently stops after SEARCH_RESULT_LIMIT results, so a
+		// partial list is only returned when asked for.
+		count := int(query.Search.RepositoryCount)
+		if count > SEARCH_RESULT_LIMIT && !d.Get(SEARCH_ALLOW_TRUNCATED).(bool) {
+			return fmt.Errorf("error search %q matched %d repositories but GitHub returns at most %d; narrow the query or set %s", q, count, SEARCH_RESULT_LIMIT, SEARCH_ALLOW_TRUNCATED)
+		}
+
+		for _, n := range query.Search.Nodes {
+			repositories = append(repositories, flattenRepositoryDetails(n.Repository))
+		}
+
+		if !query.Search.PageInfo.HasNextPage {
+			break
+		}
+
+		variables["cursor"] = githubv4.NewString(query.Search.PageInfo.EndCursor)
+	}
+
+	err := d.Set(ORGANIZATION_REPOSITORIES, repositories)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(SEARCH_TOTAL_COUNT, int(query.Search.RepositoryCount))
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(SEARCH_TRUNCATED, int(query.Search.RepositoryCount) > len(repositories))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/search/%s", meta.(*Organization).Name, q))
+
+	return nil
+}
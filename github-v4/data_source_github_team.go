@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -112,6 +111,7 @@ func dataSourceGithubTeamRead(d *schema.ResourceData, meta interface{}) error {
 		"immediateOnly":   githubv4.Boolean(true),
 		"membersFirst":    githubv4.Int(10),
 		"membersCursor":   (*githubv4.String)(nil),
+		"membership":      githubv4.TeamMembershipTypeAll,
 	}
 	var allMembers []struct {
 		Node User
@@ -121,7 +121,7 @@ func dataSourceGithubTeamRead(d *schema.ResourceData, meta interface{}) error {
 		ID   githubv4.ID
 		Slug githubv4.String
 	}
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 	for {
 		err := client.Query(ctx, &query, variables)
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGithubTeamRepositories() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			TEAM_SLUG: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// Computed
+			TEAM_REPOSITORIES: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						REPOSITORY_ID: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						REPOSITORY_NAME: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						TEAM_PERMISSION: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+
+		Read: dataSourceGithubTeamRepositoriesRead,
+	}
+}
+
+func dataSourceGithubTeamRepositoriesRead(d *schema.ResourceData, meta interface{}) error {
+	teamID, allRepositories, err := getTeamRepositories(d.Get(TEAM_SLUG).(string), meta)
+	if err != nil {
+		return err
+	}
+
+	var repositories []map[string]interface{}
+	for _, r := range allRepositories {
+		repository := make(map[string]interface{})
+		repository[REPOSITORY_ID] = fmt.Sprintf("%s", r.Node.ID)
+		repository[REPOSITORY_NAME] = string(r.Node.Name)
+		repository[TEAM_PERMISSION] = string(r.Permission)
+		repositories = append(repositories, repository)
+	}
+	err = d.Set(TEAM_REPOSITORIES, repositories)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/repositories", teamID))
+
+	return nil
+}
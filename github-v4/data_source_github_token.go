@@ -26,7 +26,12 @@ func dataSourceGithubToken() *schema.Resource {
 }
 
 func resourceGithubTokenRead(d *schema.ResourceData, meta interface{}) error {
-	d.Set(TOKEN, meta.(*Organization).Token)
+	token, err := meta.(*Organization).TokenSource.Token()
+	if err != nil {
+		return err
+	}
+
+	d.Set(TOKEN, token.AccessToken)
 
 	d.SetId(fmt.Sprintf("%s/token", meta.(*Organization).Name))
 
@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
@@ -40,7 +39,7 @@ func dataSourceGithubUserRead(d *schema.ResourceData, meta interface{}) error {
 		"login": githubv4.String(d.Get(USER_LOGIN).(string)),
 	}
 
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 	err := client.Query(ctx, &query, variables)
 	if err != nil {
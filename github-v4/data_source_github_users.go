@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"crypto/sha1"
 	"encoding/base64"
 	"fmt"
@@ -78,7 +77,7 @@ func dataSourceGithubUsersRead(d *schema.ResourceData, meta interface{}) error {
 			"login": githubv4.String(login),
 		}
 
-		ctx := context.Background()
+		ctx := meta.(*Organization).StopContext
 		client := meta.(*Organization).Client
 		err := client.Query(ctx, &query, variables)
 		if err != nil {
@@ -3,6 +3,7 @@ package github
 import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -28,6 +29,20 @@ func Provider() terraform.ResourceProvider {
 				Description: "The GitHub access token.",
 				Sensitive:   true,
 			},
+			PROVIDER_MAX_RETRIES: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GITHUB_MAX_RETRIES", 3),
+				Description:  "The number of times a rate limited or failed query is retried.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			PROVIDER_POINT_BUDGET: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GITHUB_POINT_BUDGET", 0),
+				Description:  "The maximum number of GraphQL rate limit points to spend in a single run. 0 is unlimited.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 			PROVIDER_APP: {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -57,16 +72,31 @@ func Provider() terraform.ResourceProvider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"github_branch_protection": resourceGithubBranchProtection(),
+			"github_branch":                  resourceGithubBranch(),
+			"github_branch_protection":       resourceGithubBranchProtection(),
+			"github_codeowners":              resourceGithubCodeowners(),
+			"github_membership":              resourceGithubMembership(),
+			"github_repository":              resourceGithubRepository(),
+			"github_repository_collaborator": resourceGithubRepositoryCollaborator(),
+			"github_repository_file":         resourceGithubRepositoryFile(),
+			"github_repository_ruleset":      resourceGithubRepositoryRuleset(),
+			"github_tag":                     resourceGithubTag(),
+			"github_team":                    resourceGithubTeam(),
+			"github_team_members":            resourceGithubTeamMembers(),
+			"github_team_repository":         resourceGithubTeamRepository(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
+			"github_branch_protection_rules":  dataSourceGithubBranchProtectionRules(),
 			"github_codeowners":               dataSourceGithubCodeowners(),
 			"github_ip_ranges":                dataSourceGithubIpRanges(),
+			"github_organization_invitations": dataSourceGithubOrganizationInvitations(),
 			"github_organization_members":     dataSourceGithubOrganizationMembers(),
 			"github_repositories":             dataSourceGithubRepositories(),
 			"github_repository":               dataSourceGithubRepository(),
 			"github_repository_collaborators": dataSourceGithubRepositoryCollaborators(),
+			"github_repository_search":        dataSourceGithubRepositorySearch(),
 			"github_team":                     dataSourceGithubTeam(),
+			"github_team_repositories":        dataSourceGithubTeamRepositories(),
 			"github_token":                    dataSourceGithubToken(),
 			"github_user":                     dataSourceGithubUser(),
 			"github_users":                    dataSourceGithubUsers(),
@@ -84,6 +114,8 @@ func providerConfigure(p *schema.Provider) schema.ConfigureFunc {
 			baseURL      = d.Get(PROVIDER_BASE_URL).(string)
 			organization = d.Get(PROVIDER_ORGANIZATION).(string)
 			token        = d.Get(PROVIDER_TOKEN).(string)
+			maxRetries   = d.Get(PROVIDER_MAX_RETRIES).(int)
+			pointBudget  = d.Get(PROVIDER_POINT_BUDGET).(int)
 			appPEM       = ""
 			appID        = ""
 			appInstID    = ""
@@ -119,6 +151,8 @@ func providerConfigure(p *schema.Provider) schema.ConfigureFunc {
 			Pem:            appPEM,
 			AppID:          appID,
 			InstallationID: appInstID,
+			MaxRetries:     maxRetries,
+			PointBudget:    pointBudget,
 		}
 
 		meta, err := config.Clients()
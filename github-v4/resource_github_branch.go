@@ -0,0 +1,283 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strings"
+	"time"
+)
+
+func resourceGithubBranch() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: refSchema(REF_BRANCH, "The name of the branch, without refs/heads/."),
+
+		Create: resourceGithubRefCreate(REF_NAMESPACE_BRANCH, REF_BRANCH),
+		Read:   resourceGithubRefRead(REF_NAMESPACE_BRANCH, REF_BRANCH),
+		Update: resourceGithubRefUpdate(REF_NAMESPACE_BRANCH, REF_BRANCH),
+		Delete: resourceGithubRefDelete(REF_NAMESPACE_BRANCH),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRefImport,
+		},
+	}
+}
+
+// refSchema is shared by github_branch and github_tag, which differ only in
+// the namespace of the ref and the name of its attribute.
+func refSchema(name string, description string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		// Input
+		REPOSITORY_ID: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The node ID of the repository.",
+		},
+		name: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: description,
+		},
+		REF_SOURCE_BRANCH: {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{REF_SOURCE_SHA, REF_SOURCE_TAG},
+			Description:   "The branch to start from; defaults to the default branch.",
+		},
+		REF_SOURCE_TAG: {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{REF_SOURCE_BRANCH, REF_SOURCE_SHA},
+		},
+		REF_SOURCE_SHA: {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{REF_SOURCE_BRANCH, REF_SOURCE_TAG},
+		},
+		REF_FORCE: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Allow a change of source to move the ref to a commit which does not descend from its current one.",
+		},
+		// Computed
+		REF_NAME: {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		REF_SHA: {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func resourceGithubRefCreate(namespace string, name string) schema.CreateFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		repositoryID := d.Get(REPOSITORY_ID).(string)
+
+		ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+		defer cancel()
+
+		source := refSource(d.Get(REF_SOURCE_BRANCH).(string), d.Get(REF_SOURCE_TAG).(string), d.Get(REF_SOURCE_SHA).(string))
+		if source == "" {
+			branch, err := getRepositoryDefaultBranch(ctx, repositoryID, meta)
+			if err != nil {
+				return err
+			}
+			if branch == "" {
+				return fmt.Errorf("error repository %s has no default branch; set one of %s, %s or %s", repositoryID, REF_SOURCE_BRANCH, REF_SOURCE_TAG, REF_SOURCE_SHA)
+			}
+			source = REF_NAMESPACE_BRANCH + branch
+		}
+
+		oid, err := resolveCommit(ctx, repositoryID, source, meta)
+		if err != nil {
+			return err
+		}
+
+		_, err = createRef(ctx, repositoryID, namespace+d.Get(name).(string), oid, meta)
+		if err != nil {
+			return err
+		}
+
+		d.SetId(fmt.Sprintf("%s:%s", repositoryID, d.Get(name).(string)))
+
+		return resourceGithubRefRead(namespace, name)(d, meta)
+	}
+}
+
+func resourceGithubRefRead(namespace string, name string) schema.ReadFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		repositoryID, refName, err := parseRefID(d.Id())
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+		defer cancel()
+
+		ref, err := getRef(ctx, repositoryID, namespace+refName, meta)
+		if err != nil {
+			if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+				log.Printf("[WARN] Removing ref (%s) from state because the repository no longer exists in GitHub", d.Id())
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+		if ref.ID == nil {
+			log.Printf("[WARN] Removing ref (%s) from state because it no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		err = d.Set(REPOSITORY_ID, repositoryID)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in ref (%s)", REPOSITORY_ID, d.Id())
+		}
+
+		err = d.Set(name, refName)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in ref (%s)", name, d.Id())
+		}
+
+		err = d.Set(REF_NAME, string(ref.Prefix+ref.Name))
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in ref (%s)", REF_NAME, d.Id())
+		}
+
+		err = d.Set(REF_SHA, string(ref.Target.Oid))
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in ref (%s)", REF_SHA, d.Id())
+		}
+
+		return nil
+	}
+}
+
+func resourceGithubRefUpdate(namespace string, name string) schema.UpdateFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		// The ref only moves when its source is changed; commits pushed to
+		// it, or to the source branch, are left alone.
+		if !d.HasChange(REF_SOURCE_BRANCH) && !d.HasChange(REF_SOURCE_TAG) && !d.HasChange(REF_SOURCE_SHA) {
+			return resourceGithubRefRead(namespace, name)(d, meta)
+		}
+
+		repositoryID, refName, err := parseRefID(d.Id())
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
+
+		source := refSource(d.Get(REF_SOURCE_BRANCH).(string), d.Get(REF_SOURCE_TAG).(string), d.Get(REF_SOURCE_SHA).(string))
+		if source == "" {
+			branch, err := getRepositoryDefaultBranch(ctx, repositoryID, meta)
+			if err != nil {
+				return err
+			}
+			source = REF_NAMESPACE_BRANCH + branch
+		}
+
+		oid, err := resolveCommit(ctx, repositoryID, source, meta)
+		if err != nil {
+			return err
+		}
+
+		ref, err := getRef(ctx, repositoryID, namespace+refName, meta)
+		if err != nil {
+			return err
+		}
+		if ref.ID == nil {
+			return fmt.Errorf("error %s%s no longer exists in repository %s", namespace, refName, repositoryID)
+		}
+
+		err = updateRef(ctx, ref.ID, oid, d.Get(REF_FORCE).(bool), meta)
+		if err != nil {
+			return err
+		}
+
+		return resourceGithubRefRead(namespace, name)(d, meta)
+	}
+}
+
+func resourceGithubRefDelete(namespace string) schema.DeleteFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		repositoryID, refName, err := parseRefID(d.Id())
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+		defer cancel()
+
+		ref, err := getRef(ctx, repositoryID, namespace+refName, meta)
+		if err != nil {
+			if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+				return nil
+			}
+
+			return err
+		}
+		if ref.ID == nil {
+			return nil
+		}
+
+		return deleteRef(ctx, ref.ID, meta)
+	}
+}
+
+// resourceGithubRefImport accepts `repository:name`. The source of the ref is
+// not recorded by git, so it is left unset; setting one later moves the ref
+// like any other change of source.
+func resourceGithubRefImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("error invalid import ID %q, expected <repository>:<name>", d.Id())
+	}
+
+	repositoryID, err := getRepositoryID(parts[0], meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", repositoryID, parts[1]))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseRefID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("error unexpected ref ID %q", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// refSource returns the expression naming the source commit, or "" if no
+// source is set.
+func refSource(branch string, tag string, sha string) string {
+	switch {
+	case branch != "":
+		return REF_NAMESPACE_BRANCH + branch
+	case tag != "":
+		return REF_NAMESPACE_TAG + tag
+	default:
+		return sha
+	}
+}
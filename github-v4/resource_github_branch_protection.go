@@ -8,11 +8,12 @@ import (
 	"github.com/shurcooL/githubv4"
 	"log"
 	"strings"
+	"time"
 )
 
 func resourceGithubBranchProtection() *schema.Resource {
 	return &schema.Resource{
-		SchemaVersion: 1,
+		SchemaVersion: 3,
 
 		Schema: map[string]*schema.Schema{
 			// Input
@@ -37,6 +38,41 @@ func resourceGithubBranchProtection() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			PROTECTION_REQUIRES_LINEAR_HISTORY: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_CONVERSATION_RESOLUTION: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_ALLOWS_DELETIONS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_ALLOWS_FORCE_PUSHES: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_BLOCKS_CREATIONS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_LOCK_BRANCH: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			PROTECTION_REQUIRES_APPROVING_REVIEWS: {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -52,14 +88,28 @@ func resourceGithubBranchProtection() *schema.Resource {
 							Type:     schema.TypeBool,
 							Optional: true,
 						},
+						PROTECTION_REQUIRE_LAST_PUSH_APPROVAL: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
 						PROTECTION_DISMISSES_STALE_REVIEWS: {
 							Type:     schema.TypeBool,
 							Optional: true,
 						},
-						PROTECTION_RESTRICTS_REVIEW_DISMISSALS: {
+						PROTECTION_RESTRICT_DISMISSALS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+						},
+						PROTECTION_REVIEW_DISMISSAL_ALLOWANCES: {
 							Type:     schema.TypeSet,
 							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
+							Elem:     branchProtectionActorResource(),
+						},
+						PROTECTION_BYPASS_PULL_REQUEST_ALLOWANCES: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     branchProtectionActorResource(),
 						},
 					},
 				},
@@ -73,18 +123,35 @@ func resourceGithubBranchProtection() *schema.Resource {
 							Type:     schema.TypeBool,
 							Optional: true,
 						},
-						PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS: {
+						PROTECTION_REQUIRED_STATUS_CHECKS: {
 							Type:     schema.TypeSet,
 							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									PROTECTION_STATUS_CHECK_CONTEXT: {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									PROTECTION_STATUS_CHECK_APP_ID: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "The node ID of the App that must set the status. Any App is accepted when empty.",
+									},
+								},
+							},
 						},
 					},
 				},
 			},
-			PROTECTION_RESTRICTS_PUSHES: {
+			PROTECTION_PUSH_ALLOWANCES: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     branchProtectionActorResource(),
+			},
+			PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES: {
 				Type:     schema.TypeSet,
 				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem:     branchProtectionActorResource(),
 			},
 		},
 
@@ -93,8 +160,15 @@ func resourceGithubBranchProtection() *schema.Resource {
 		Update: resourceGithubBranchProtectionUpdate,
 		Delete: resourceGithubBranchProtectionDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceGithubBranchProtectionImport,
 		},
 
 		StateUpgraders: []schema.StateUpgrader{
@@ -103,6 +177,46 @@ func resourceGithubBranchProtection() *schema.Resource {
 				Upgrade: resourceGithubBranchProtectionUpgradeV0,
 				Version: 0,
 			},
+			{
+				Type:    resourceGithubBranchProtectionV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceGithubBranchProtectionUpgradeV1,
+				Version: 1,
+			},
+			{
+				Type:    resourceGithubBranchProtectionV2().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceGithubBranchProtectionUpgradeV2,
+				Version: 2,
+			},
+		},
+	}
+}
+
+func branchProtectionActorResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			ACTOR_APP: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The slug of a GitHub App.",
+			},
+			ACTOR_TEAM: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The slug of a team in the organization.",
+			},
+			ACTOR_USER: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The login of a user.",
+			},
+			ACTOR_ID: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ACTOR_TYPE: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -119,25 +233,14 @@ func resourceGithubBranchProtectionCreate(d *schema.ResourceData, meta interface
 	if err != nil {
 		return err
 	}
-	input := githubv4.CreateBranchProtectionRuleInput{
-		DismissesStaleReviews:        githubv4.NewBoolean(githubv4.Boolean(data.DismissesStaleReviews)),
-		IsAdminEnforced:              githubv4.NewBoolean(githubv4.Boolean(data.IsAdminEnforced)),
-		Pattern:                      githubv4.String(data.Pattern),
-		PushActorIDs:                 githubv4NewIDSlice(githubv4IDSlice(data.PushActorIDs)),
-		RepositoryID:                 githubv4.NewID(githubv4.ID(data.RepositoryID)),
-		RequiredApprovingReviewCount: githubv4.NewInt(githubv4.Int(data.RequiredApprovingReviewCount)),
-		RequiredStatusCheckContexts:  githubv4NewStringSlice(githubv4StringSlice(data.RequiredStatusCheckContexts)),
-		RequiresApprovingReviews:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresApprovingReviews)),
-		RequiresCodeOwnerReviews:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresCodeOwnerReviews)),
-		RequiresCommitSignatures:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresCommitSignatures)),
-		RequiresStatusChecks:         githubv4.NewBoolean(githubv4.Boolean(data.RequiresStatusChecks)),
-		RequiresStrictStatusChecks:   githubv4.NewBoolean(githubv4.Boolean(data.RequiresStrictStatusChecks)),
-		RestrictsPushes:              githubv4.NewBoolean(githubv4.Boolean(data.RestrictsPushes)),
-		RestrictsReviewDismissals:    githubv4.NewBoolean(githubv4.Boolean(data.RestrictsReviewDismissals)),
-		ReviewDismissalActorIDs:      githubv4NewIDSlice(githubv4IDSlice(data.ReviewDismissalActorIDs)),
-	}
-
-	ctx := context.Background()
+	input := CreateBranchProtectionRuleInput{
+		RepositoryID:              githubv4.ID(data.RepositoryID),
+		Pattern:                   githubv4.String(data.Pattern),
+		branchProtectionRuleInput: newBranchProtectionRuleInput(data),
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 	client := meta.(*Organization).Client
 	err = client.Mutate(ctx, &mutate, input, nil)
 	if err != nil {
@@ -150,18 +253,9 @@ func resourceGithubBranchProtectionCreate(d *schema.ResourceData, meta interface
 }
 
 func resourceGithubBranchProtectionRead(d *schema.ResourceData, meta interface{}) error {
-	var query struct {
-		Node struct {
-			Node BranchProtectionRule `graphql:"... on BranchProtectionRule"`
-		} `graphql:"node(id: $id)"`
-	}
-	variables := map[string]interface{}{
-		"id": d.Id(),
-	}
-
-	ctx := context.WithValue(context.Background(), "id", d.Id())
-	client := meta.(*Organization).Client
-	err := client.Query(ctx, &query, variables)
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	protection, err := getBranchProtectionRule(ctx, d.Id(), meta)
 	if err != nil {
 		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
 			log.Printf("[WARN] Removing branch protection (%s) from state because it no longer exists in GitHub", d.Id())
@@ -172,7 +266,10 @@ func resourceGithubBranchProtectionRead(d *schema.ResourceData, meta interface{}
 		return err
 	}
 
-	protection := query.Node.Node
+	err = d.Set(REPOSITORY_ID, protection.Repository.ID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", REPOSITORY_ID, protection.Repository.Name, protection.Pattern, d.Id())
+	}
 
 	err = d.Set(PROTECTION_PATTERN, protection.Pattern)
 	if err != nil {
@@ -189,6 +286,41 @@ func resourceGithubBranchProtectionRead(d *schema.ResourceData, meta interface{}
 		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_REQUIRES_COMMIT_SIGNATURES, protection.Repository.Name, protection.Pattern, d.Id())
 	}
 
+	err = d.Set(PROTECTION_REQUIRES_LINEAR_HISTORY, protection.RequiresLinearHistory)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_REQUIRES_LINEAR_HISTORY, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	err = d.Set(PROTECTION_REQUIRES_CONVERSATION_RESOLUTION, protection.RequiresConversationResolution)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_REQUIRES_CONVERSATION_RESOLUTION, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	err = d.Set(PROTECTION_ALLOWS_DELETIONS, protection.AllowsDeletions)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_ALLOWS_DELETIONS, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	err = d.Set(PROTECTION_ALLOWS_FORCE_PUSHES, protection.AllowsForcePushes)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_ALLOWS_FORCE_PUSHES, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	err = d.Set(PROTECTION_BLOCKS_CREATIONS, protection.BlocksCreations)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_BLOCKS_CREATIONS, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	err = d.Set(PROTECTION_LOCK_BRANCH, protection.LockBranch)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_LOCK_BRANCH, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	err = d.Set(PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE, protection.LockAllowsFetchAndMerge)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
 	approvingReviews := setApprovingReviews(protection)
 	err = d.Set(PROTECTION_REQUIRES_APPROVING_REVIEWS, approvingReviews)
 	if err != nil {
@@ -201,10 +333,16 @@ func resourceGithubBranchProtectionRead(d *schema.ResourceData, meta interface{}
 		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_REQUIRES_STATUS_CHECKS, protection.Repository.Name, protection.Pattern, d.Id())
 	}
 
-	restrictsPushes := setPushes(protection)
-	err = d.Set(PROTECTION_RESTRICTS_PUSHES, restrictsPushes)
+	pushAllowances := setPushes(protection)
+	err = d.Set(PROTECTION_PUSH_ALLOWANCES, pushAllowances)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_PUSH_ALLOWANCES, protection.Repository.Name, protection.Pattern, d.Id())
+	}
+
+	bypassForcePushAllowances := flattenAllowances(protection.BypassForcePushAllowances.Nodes)
+	err = d.Set(PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES, bypassForcePushAllowances)
 	if err != nil {
-		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_RESTRICTS_PUSHES, protection.Repository.Name, protection.Pattern, d.Id())
+		log.Printf("[WARN] Problem setting '%s' in %s %s branch protection (%s)", PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES, protection.Repository.Name, protection.Pattern, d.Id())
 	}
 
 	return nil
@@ -222,25 +360,14 @@ func resourceGithubBranchProtectionUpdate(d *schema.ResourceData, meta interface
 	if err != nil {
 		return err
 	}
-	input := githubv4.UpdateBranchProtectionRuleInput{
-		BranchProtectionRuleID:       d.Id(),
-		DismissesStaleReviews:        githubv4.NewBoolean(githubv4.Boolean(data.DismissesStaleReviews)),
-		IsAdminEnforced:              githubv4.NewBoolean(githubv4.Boolean(data.IsAdminEnforced)),
-		Pattern:                      githubv4.NewString(githubv4.String(data.Pattern)),
-		PushActorIDs:                 githubv4NewIDSlice(githubv4IDSlice(data.PushActorIDs)),
-		RequiredApprovingReviewCount: githubv4.NewInt(githubv4.Int(data.RequiredApprovingReviewCount)),
-		RequiredStatusCheckContexts:  githubv4NewStringSlice(githubv4StringSlice(data.RequiredStatusCheckContexts)),
-		RequiresApprovingReviews:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresApprovingReviews)),
-		RequiresCodeOwnerReviews:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresCodeOwnerReviews)),
-		RequiresCommitSignatures:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresCommitSignatures)),
-		RequiresStatusChecks:         githubv4.NewBoolean(githubv4.Boolean(data.RequiresStatusChecks)),
-		RequiresStrictStatusChecks:   githubv4.NewBoolean(githubv4.Boolean(data.RequiresStrictStatusChecks)),
-		RestrictsPushes:              githubv4.NewBoolean(githubv4.Boolean(data.RestrictsPushes)),
-		RestrictsReviewDismissals:    githubv4.NewBoolean(githubv4.Boolean(data.RestrictsReviewDismissals)),
-		ReviewDismissalActorIDs:      githubv4NewIDSlice(githubv4IDSlice(data.ReviewDismissalActorIDs)),
-	}
-
-	ctx := context.WithValue(context.Background(), "id", d.Id())
+	input := UpdateBranchProtectionRuleInput{
+		BranchProtectionRuleID:    d.Id(),
+		Pattern:                   githubv4.NewString(githubv4.String(data.Pattern)),
+		branchProtectionRuleInput: newBranchProtectionRuleInput(data),
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
 	client := meta.(*Organization).Client
 	err = client.Mutate(ctx, &mutate, input, nil)
 	if err != nil {
@@ -262,9 +389,40 @@ func resourceGithubBranchProtectionDelete(d *schema.ResourceData, meta interface
 		BranchProtectionRuleID: d.Id(),
 	}
 
-	ctx := context.WithValue(context.Background(), "id", d.Id())
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 	client := meta.(*Organization).Client
 	err := client.Mutate(ctx, &mutate, input, nil)
 
 	return err
 }
+
+// resourceGithubBranchProtectionImport accepts either a BranchProtectionRule
+// node ID or `repo:pattern` / `owner/repo:pattern`.
+func resourceGithubBranchProtectionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	owner := meta.(*Organization).Name
+	name, pattern := parts[0], parts[1]
+	if i := strings.Index(name, "/"); i >= 0 {
+		owner, name = name[:i], name[i+1:]
+	}
+	if owner == "" || name == "" || pattern == "" {
+		return nil, fmt.Errorf("error invalid import ID %q, expected <repository>:<pattern> or <owner>/<repository>:<pattern>", d.Id())
+	}
+
+	id, err := getBranchProtectionID(owner, name, pattern, meta)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil || id == "" {
+		return nil, fmt.Errorf("error no branch protection rule matches pattern %q in %s/%s", pattern, owner, name)
+	}
+
+	d.SetId(fmt.Sprintf("%s", id))
+
+	return []*schema.ResourceData{d}, nil
+}
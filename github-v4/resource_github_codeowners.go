@@ -0,0 +1,395 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"strings"
+	"time"
+)
+
+func resourceGithubCodeowners() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node ID of the repository.",
+			},
+			CODEOWNERS_RULE: {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						CODEOWNERS_PATTERN: {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						CODEOWNERS_OWNERS: {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			COMMIT_BRANCH: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The branch to commit to; defaults to the default branch.",
+			},
+			COMMIT_MESSAGE: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "Update CODEOWNERS",
+			},
+			PULL_REQUEST: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Open a pull request into the branch rather than committing to it directly.",
+			},
+			PULL_REQUEST_BRANCH: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "terraform/codeowners",
+				Description: "The branch the pull request is opened from. It is reset on every change.",
+			},
+			// Computed
+			COMMIT_SHA: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			PULL_REQUEST_NUMBER: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			PULL_REQUEST_URL: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Create: resourceGithubCodeownersCreate,
+		Read:   resourceGithubCodeownersRead,
+		Update: resourceGithubCodeownersUpdate,
+		Delete: resourceGithubCodeownersDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRepositoryImport,
+		},
+	}
+}
+
+func resourceGithubCodeownersCreate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	content := renderCodeowners(expandCodeownersRules(d))
+	err := writeCodeowners(ctx, d, &content, meta)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(d.Get(REPOSITORY_ID).(string))
+
+	return resourceGithubCodeownersRead(d, meta)
+}
+
+func resourceGithubCodeownersRead(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	branch := d.Get(COMMIT_BRANCH).(string)
+	if branch == "" {
+		var err error
+		branch, err = getRepositoryDefaultBranch(ctx, d.Id(), meta)
+		if err != nil {
+			if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+				log.Printf("[WARN] Removing CODEOWNERS (%s) from state because the repository no longer exists in GitHub", d.Id())
+				d.SetId("")
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	// While a pull request is open its branch holds the desired content;
+	// once merged or closed the target branch is the source of truth.
+	ref := branch
+	number := d.Get(PULL_REQUEST_NUMBER).(int)
+	url := d.Get(PULL_REQUEST_URL).(string)
+	if number != 0 {
+		pr, err := getPullRequest(ctx, d.Id(), number, meta)
+		if err != nil {
+			return err
+		}
+		if pr.State == githubv4.PullRequestStateOpen {
+			ref = string(pr.HeadRefName)
+		} else {
+			number, url = 0, ""
+		}
+	}
+
+	text, _, err := getFileContent(ctx, d.Id(), ref, CODEOWNERS_PATHS[0], meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing CODEOWNERS (%s) from state because the repository no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	var rules []map[string]interface{}
+	for _, r := range parseCodeowners(text) {
+		owners := make([]interface{}, 0, len(r.Owners))
+		for _, o := range r.Owners {
+			owners = append(owners, o)
+		}
+		rules = append(rules, map[string]interface{}{
+			CODEOWNERS_PATTERN: r.Pattern,
+			CODEOWNERS_OWNERS:  owners,
+		})
+	}
+
+	err = d.Set(REPOSITORY_ID, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", REPOSITORY_ID, d.Id())
+	}
+
+	err = d.Set(COMMIT_BRANCH, branch)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", COMMIT_BRANCH, d.Id())
+	}
+
+	err = d.Set(CODEOWNERS_RULE, rules)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", CODEOWNERS_RULE, d.Id())
+	}
+
+	err = d.Set(PULL_REQUEST_NUMBER, number)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", PULL_REQUEST_NUMBER, d.Id())
+	}
+
+	err = d.Set(PULL_REQUEST_URL, url)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", PULL_REQUEST_URL, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubCodeownersUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	content := renderCodeowners(expandCodeownersRules(d))
+	err := writeCodeowners(ctx, d, &content, meta)
+	if err != nil {
+		return err
+	}
+
+	return resourceGithubCodeownersRead(d, meta)
+}
+
+func resourceGithubCodeownersDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	if d.Get(PULL_REQUEST).(bool) {
+		return abandonCodeownersPullRequest(ctx, d, meta)
+	}
+
+	return writeCodeowners(ctx, d, nil, meta)
+}
+
+// abandonCodeownersPullRequest closes any open pull request and deletes its
+// branch. Whatever has already been merged stays on the target branch, as
+// removing it would need yet another pull request.
+func abandonCodeownersPullRequest(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	repositoryID := d.Get(REPOSITORY_ID).(string)
+
+	number := d.Get(PULL_REQUEST_NUMBER).(int)
+	if number != 0 {
+		pr, err := getPullRequest(ctx, repositoryID, number, meta)
+		if err != nil {
+			return err
+		}
+		if pr.State == githubv4.PullRequestStateOpen {
+			log.Printf("[DEBUG] Closing pull request #%d for CODEOWNERS (%s)", number, d.Id())
+			err = closePullRequest(ctx, pr.ID, meta)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ref, err := getBranchRef(ctx, repositoryID, d.Get(PULL_REQUEST_BRANCH).(string), meta)
+	if err != nil {
+		return err
+	}
+	if ref.ID != nil {
+		err = deleteRef(ctx, ref.ID, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Leaving CODEOWNERS on branch %s of %s", d.Get(COMMIT_BRANCH).(string), repositoryID)
+
+	return nil
+}
+
+func expandCodeownersRules(d *schema.ResourceData) []CodeownersRule {
+	var rules []CodeownersRule
+	for _, v := range d.Get(CODEOWNERS_RULE).([]interface{}) {
+		m := v.(map[string]interface{})
+		rule := CodeownersRule{
+			Pattern: m[CODEOWNERS_PATTERN].(string),
+		}
+		for _, o := range m[CODEOWNERS_OWNERS].([]interface{}) {
+			rule.Owners = append(rule.Owners, o.(string))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// writeCodeowners commits content to .github/CODEOWNERS, or deletes the file
+// when content is nil, either directly on the target branch or through a
+// pull request. Nothing is committed if the file is already as desired.
+func writeCodeowners(ctx context.Context, d *schema.ResourceData, content *string, meta interface{}) error {
+	repositoryID := d.Get(REPOSITORY_ID).(string)
+	path := CODEOWNERS_PATHS[0]
+
+	nameWithOwner, err := getRepositoryNameWithOwner(ctx, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	branch := d.Get(COMMIT_BRANCH).(string)
+	if branch == "" {
+		branch, err = getRepositoryDefaultBranch(ctx, repositoryID, meta)
+		if err != nil {
+			return err
+		}
+		if branch == "" {
+			return fmt.Errorf("error %s has no default branch to commit CODEOWNERS to", nameWithOwner)
+		}
+		err = d.Set(COMMIT_BRANCH, branch)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", COMMIT_BRANCH, d.Id())
+		}
+	}
+
+	base, err := getBranchRef(ctx, repositoryID, branch, meta)
+	if err != nil {
+		return err
+	}
+	if base.ID == nil {
+		return fmt.Errorf("error branch %s does not exist in %s", branch, nameWithOwner)
+	}
+
+	// Only the file's presence matters when deleting it.
+	unchanged := func(ref string) (bool, error) {
+		current, oid, err := getFileContent(ctx, repositoryID, ref, path, meta)
+		if err != nil {
+			return false, err
+		}
+		if content == nil {
+			return oid == "", nil
+		}
+		return oid != "" && current == *content, nil
+	}
+
+	done, err := unchanged(branch)
+	if err != nil || done {
+		return err
+	}
+
+	target := branch
+	pullRequest := d.Get(PULL_REQUEST).(bool)
+	if pullRequest {
+		target = d.Get(PULL_REQUEST_BRANCH).(string)
+
+		// Leave an open pull request alone if it already proposes the
+		// desired content, so its branch is only reset to pick up new
+		// changes.
+		number := d.Get(PULL_REQUEST_NUMBER).(int)
+		if number != 0 {
+			pr, err := getPullRequest(ctx, repositoryID, number, meta)
+			if err != nil {
+				return err
+			}
+			if pr.State == githubv4.PullRequestStateOpen {
+				done, err = unchanged(string(pr.HeadRefName))
+				if err != nil || done {
+					return err
+				}
+			}
+		}
+
+		err = resetBranch(ctx, repositoryID, target, base.Target.Oid, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	var additions map[string]string
+	var deletions []string
+	if content == nil {
+		deletions = []string{path}
+	} else {
+		additions = map[string]string{path: *content}
+	}
+
+	sha, err := commitOnBranch(ctx, nameWithOwner, target, base.Target.Oid, d.Get(COMMIT_MESSAGE).(string), "", additions, deletions, meta)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(COMMIT_SHA, sha)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", COMMIT_SHA, d.Id())
+	}
+
+	if pullRequest {
+		pr, err := ensurePullRequest(ctx, repositoryID, branch, target, d.Get(COMMIT_MESSAGE).(string), meta)
+		if err != nil {
+			return err
+		}
+
+		err = d.Set(PULL_REQUEST_NUMBER, int(pr.Number))
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", PULL_REQUEST_NUMBER, d.Id())
+		}
+
+		err = d.Set(PULL_REQUEST_URL, pr.URL.String())
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in CODEOWNERS (%s)", PULL_REQUEST_URL, d.Id())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,309 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceGithubMembership() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			USER_LOGIN: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{INVITATION_EMAIL},
+				Description:   "The login of the user to invite.",
+			},
+			INVITATION_EMAIL: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{USER_LOGIN},
+				Description:   "The email address to invite, for people without a GitHub account.",
+			},
+			USER_ROLE: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(githubv4.OrganizationMemberRoleMember),
+				ValidateFunc: validation.StringInSlice([]string{
+					string(githubv4.OrganizationMemberRoleAdmin),
+					string(githubv4.OrganizationMemberRoleMember),
+				}, false),
+			},
+			// Computed
+			INVITATION_ID: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			MEMBERSHIP_STATE: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Create: resourceGithubMembershipCreate,
+		Read:   resourceGithubMembershipRead,
+		Update: resourceGithubMembershipUpdate,
+		Delete: resourceGithubMembershipDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+func resourceGithubMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	login := d.Get(USER_LOGIN).(string)
+	email := d.Get(INVITATION_EMAIL).(string)
+	role := d.Get(USER_ROLE).(string)
+	if login == "" && email == "" {
+		return fmt.Errorf("error one of %s or %s must be set", USER_LOGIN, INVITATION_EMAIL)
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	// GraphQL has no mutations for organization membership.
+	if login != "" {
+		err := setOrganizationMembership(ctx, login, role, meta)
+		if err != nil {
+			return err
+		}
+		d.SetId(login)
+	} else {
+		err := inviteOrganizationEmail(ctx, email, role, meta)
+		if err != nil {
+			return err
+		}
+		d.SetId(email)
+	}
+
+	return resourceGithubMembershipRead(d, meta)
+}
+
+func resourceGithubMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	if isEmailMembership(d) {
+		return readEmailMembership(ctx, d, meta)
+	}
+
+	var membership struct {
+		State string `json:"state"`
+		Role  string `json:"role"`
+	}
+	path := fmt.Sprintf("/orgs/%s/memberships/%s", meta.(*Organization).Name, d.Id())
+	status, err := restRequest(ctx, meta, "GET", path, nil, &membership)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Removing membership (%s) from state because the user is no longer a member or invited", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	invitationID := 0
+	if membership.State == MEMBERSHIP_STATE_PENDING {
+		invitations, err := getOrganizationInvitations(ctx, false, meta)
+		if err != nil {
+			return err
+		}
+		for _, i := range invitations {
+			if strings.EqualFold(i.Login, d.Id()) {
+				invitationID = i.ID
+				break
+			}
+		}
+	}
+
+	err = d.Set(USER_LOGIN, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in membership (%s)", USER_LOGIN, d.Id())
+	}
+
+	err = d.Set(USER_ROLE, organizationRole(membership.Role))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in membership (%s)", USER_ROLE, d.Id())
+	}
+
+	err = d.Set(MEMBERSHIP_STATE, membership.State)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in membership (%s)", MEMBERSHIP_STATE, d.Id())
+	}
+
+	err = d.Set(INVITATION_ID, invitationID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in membership (%s)", INVITATION_ID, d.Id())
+	}
+
+	return nil
+}
+
+// readEmailMembership follows an invitation sent to an email address. Once
+// the invitation is no longer pending it has either failed, in which case it
+// is removed from state so it will be sent again, or been accepted; the
+// account which accepted it is not reported, so the last known role is kept.
+func readEmailMembership(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	invitations, err := getOrganizationInvitations(ctx, false, meta)
+	if err != nil {
+		return err
+	}
+
+	var invitation *OrganizationInvitation
+	for i := range invitations {
+		if strings.EqualFold(invitations[i].Email, d.Id()) {
+			invitation = &invitations[i]
+			break
+		}
+	}
+
+	state := MEMBERSHIP_STATE_ACTIVE
+	if invitation != nil {
+		state = MEMBERSHIP_STATE_PENDING
+
+		err = d.Set(USER_ROLE, organizationRole(invitation.Role))
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in membership (%s)", USER_ROLE, d.Id())
+		}
+
+		err = d.Set(INVITATION_ID, invitation.ID)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in membership (%s)", INVITATION_ID, d.Id())
+		}
+	} else if d.Get(MEMBERSHIP_STATE).(string) != MEMBERSHIP_STATE_ACTIVE {
+		failed, err := getOrganizationInvitations(ctx, true, meta)
+		if err != nil {
+			return err
+		}
+		for _, i := range failed {
+			if strings.EqualFold(i.Email, d.Id()) && i.ID == d.Get(INVITATION_ID).(int) {
+				log.Printf("[WARN] Removing membership (%s) from state because the invitation failed: %s", d.Id(), i.FailedReason)
+				d.SetId("")
+				return nil
+			}
+		}
+	}
+
+	err = d.Set(INVITATION_EMAIL, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in membership (%s)", INVITATION_EMAIL, d.Id())
+	}
+
+	err = d.Set(MEMBERSHIP_STATE, state)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in membership (%s)", MEMBERSHIP_STATE, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubMembershipUpdate(d *schema.ResourceData, meta interface{}) error {
+	role := d.Get(USER_ROLE).(string)
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	if !isEmailMembership(d) {
+		err := setOrganizationMembership(ctx, d.Id(), role, meta)
+		if err != nil {
+			return err
+		}
+
+		return resourceGithubMembershipRead(d, meta)
+	}
+
+	// Invitations cannot be edited, so a pending one is replaced.
+	if d.Get(MEMBERSHIP_STATE).(string) != MEMBERSHIP_STATE_PENDING {
+		return fmt.Errorf("error the invitation to %s has been accepted; manage the membership by %s to change its role", d.Id(), USER_LOGIN)
+	}
+
+	err := cancelOrganizationInvitation(ctx, d.Get(INVITATION_ID).(int), meta)
+	if err != nil {
+		return err
+	}
+
+	err = inviteOrganizationEmail(ctx, d.Id(), role, meta)
+	if err != nil {
+		return err
+	}
+
+	return resourceGithubMembershipRead(d, meta)
+}
+
+func resourceGithubMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	if isEmailMembership(d) {
+		if d.Get(MEMBERSHIP_STATE).(string) != MEMBERSHIP_STATE_PENDING {
+			log.Printf("[WARN] Not removing %s from %s; the account which accepted the invitation is unknown", d.Id(), meta.(*Organization).Name)
+			return nil
+		}
+
+		return cancelOrganizationInvitation(ctx, d.Get(INVITATION_ID).(int), meta)
+	}
+
+	// Removing a membership also cancels a pending invitation.
+	path := fmt.Sprintf("/orgs/%s/memberships/%s", meta.(*Organization).Name, d.Id())
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+func isEmailMembership(d *schema.ResourceData) bool {
+	return strings.Contains(d.Id(), "@")
+}
+
+func setOrganizationMembership(ctx context.Context, login string, role string, meta interface{}) error {
+	path := fmt.Sprintf("/orgs/%s/memberships/%s", meta.(*Organization).Name, login)
+	body := map[string]interface{}{
+		"role": organizationRestRole(role, false),
+	}
+	_, err := restRequest(ctx, meta, "PUT", path, body, nil)
+
+	return err
+}
+
+func inviteOrganizationEmail(ctx context.Context, email string, role string, meta interface{}) error {
+	path := fmt.Sprintf("/orgs/%s/invitations", meta.(*Organization).Name)
+	body := map[string]interface{}{
+		"email": email,
+		"role":  organizationRestRole(role, true),
+	}
+	_, err := restRequest(ctx, meta, "POST", path, body, nil)
+
+	return err
+}
+
+func cancelOrganizationInvitation(ctx context.Context, id int, meta interface{}) error {
+	path := fmt.Sprintf("/orgs/%s/invitations/%d", meta.(*Organization).Name, id)
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
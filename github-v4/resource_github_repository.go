@@ -0,0 +1,382 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceGithubRepository() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_NAME: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "",
+			},
+			REPOSITORY_DESCRIPTION: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			REPOSITORY_HOMEPAGE_URL: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			REPOSITORY_VISIBILITY: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(githubv4.RepositoryVisibilityPrivate),
+				ValidateFunc: validation.StringInSlice([]string{
+					string(githubv4.RepositoryVisibilityPrivate),
+					string(githubv4.RepositoryVisibilityPublic),
+					string(githubv4.RepositoryVisibilityInternal),
+				}, false),
+			},
+			REPOSITORY_IS_TEMPLATE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			REPOSITORY_ARCHIVED: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			REPOSITORY_HAS_DISCUSSIONS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			REPOSITORY_HAS_ISSUES: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_HAS_PROJECTS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_HAS_WIKI: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_ALLOW_AUTO_MERGE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			REPOSITORY_ALLOW_MERGE_COMMIT: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_ALLOW_REBASE_MERGE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_ALLOW_SQUASH_MERGE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			REPOSITORY_DELETE_BRANCH_ON_MERGE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			REPOSITORY_DEFAULT_BRANCH: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The branch must already exist; an empty repository has no default branch.",
+			},
+		},
+
+		Create: resourceGithubRepositoryCreate,
+		Read:   resourceGithubRepositoryRead,
+		Update: resourceGithubRepositoryUpdate,
+		Delete: resourceGithubRepositoryDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRepositoryImport,
+		},
+	}
+}
+
+func resourceGithubRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
+	var mutate struct {
+		CreateRepository struct {
+			Repository struct {
+				ID githubv4.ID
+			}
+		} `graphql:"createRepository(input: $input)"`
+	}
+	data := repositoryResourceData(d)
+
+	ownerID, err := getOrganizationID(meta)
+	if err != nil {
+		return err
+	}
+	input := githubv4.CreateRepositoryInput{
+		Name:             githubv4.String(data.Name),
+		Visibility:       githubv4.RepositoryVisibility(data.Visibility),
+		OwnerID:          githubv4.NewID(ownerID),
+		Description:      githubv4.NewString(githubv4.String(data.Description)),
+		Template:         githubv4.NewBoolean(githubv4.Boolean(data.IsTemplate)),
+		HasWikiEnabled:   githubv4.NewBoolean(githubv4.Boolean(data.HasWiki)),
+		HasIssuesEnabled: githubv4.NewBoolean(githubv4.Boolean(data.HasIssues)),
+	}
+	if data.HomepageURL != "" {
+		homepageURL, err := githubv4URI(data.HomepageURL)
+		if err != nil {
+			return err
+		}
+		input.HomepageURL = homepageURL
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	client := meta.(*Organization).Client
+	err = client.Mutate(ctx, &mutate, input, nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s", mutate.CreateRepository.Repository.ID))
+
+	// createRepository only covers a subset of the settings; the rest are
+	// applied exactly as they would be on update.
+	err = updateRepository(ctx, d.Id(), data, meta)
+	if err != nil {
+		return err
+	}
+
+	err = updateRepositorySettings(ctx, data, meta)
+	if err != nil {
+		return err
+	}
+
+	if data.Archived {
+		err = setRepositoryArchived(ctx, d.Id(), true, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceGithubRepositoryRead(d, meta)
+}
+
+func resourceGithubRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	var query struct {
+		Node struct {
+			Repository Repository `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": githubv4.ID(d.Id()),
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing repository (%s) from state because it no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	repository := query.Node.Repository
+
+	values := map[string]interface{}{
+		REPOSITORY_NAME:                   repository.Name,
+		REPOSITORY_DESCRIPTION:            repository.Description,
+		REPOSITORY_HOMEPAGE_URL:           repository.HomepageURL,
+		REPOSITORY_VISIBILITY:             string(repository.Visibility),
+		REPOSITORY_IS_TEMPLATE:            repository.IsTemplate,
+		REPOSITORY_ARCHIVED:               repository.IsArchived,
+		REPOSITORY_HAS_DISCUSSIONS:        repository.HasDiscussionsEnabled,
+		REPOSITORY_HAS_ISSUES:             repository.HasIssuesEnabled,
+		REPOSITORY_HAS_PROJECTS:           repository.HasProjectsEnabled,
+		REPOSITORY_HAS_WIKI:               repository.HasWikiEnabled,
+		REPOSITORY_ALLOW_AUTO_MERGE:       repository.AutoMergeAllowed,
+		REPOSITORY_ALLOW_MERGE_COMMIT:     repository.MergeCommitAllowed,
+		REPOSITORY_ALLOW_REBASE_MERGE:     repository.RebaseMergeAllowed,
+		REPOSITORY_ALLOW_SQUASH_MERGE:     repository.SquashMergeAllowed,
+		REPOSITORY_DELETE_BRANCH_ON_MERGE: repository.DeleteBranchOnMerge,
+		REPOSITORY_DEFAULT_BRANCH:         repository.DefaultBranchRef.Name,
+	}
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in %s repository (%s)", k, repository.Name, d.Id())
+		}
+	}
+
+	return nil
+}
+
+func resourceGithubRepositoryUpdate(d *schema.ResourceData, meta interface{}) error {
+	data := repositoryResourceData(d)
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	// An archived repository is read-only, so it has to be unarchived before
+	// anything else changes and archived only once everything else has.
+	wasArchived, _ := d.GetChange(REPOSITORY_ARCHIVED)
+	if wasArchived.(bool) && data.Archived {
+		return fmt.Errorf("error repository %s is archived; set %s = false to unarchive it before changing anything else", data.Name, REPOSITORY_ARCHIVED)
+	}
+	if wasArchived.(bool) && !data.Archived {
+		err := setRepositoryArchived(ctx, d.Id(), false, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := updateRepository(ctx, d.Id(), data, meta)
+	if err != nil {
+		return err
+	}
+
+	if !d.HasChange(REPOSITORY_DEFAULT_BRANCH) {
+		data.DefaultBranch = ""
+	}
+	err = updateRepositorySettings(ctx, data, meta)
+	if err != nil {
+		return err
+	}
+
+	if !wasArchived.(bool) && data.Archived {
+		err = setRepositoryArchived(ctx, d.Id(), true, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceGithubRepositoryRead(d, meta)
+}
+
+func resourceGithubRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	// GraphQL has no mutation to delete a repository.
+	path := fmt.Sprintf("/repos/%s/%s", meta.(*Organization).Name, d.Get(REPOSITORY_NAME).(string))
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+func resourceGithubRepositoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := getRepositoryID(d.Id(), meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s", id))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func updateRepository(ctx context.Context, id string, data RepositoryResourceData, meta interface{}) error {
+	var mutate struct {
+		UpdateRepository struct {
+			Repository struct {
+				ID githubv4.ID
+			}
+		} `graphql:"updateRepository(input: $input)"`
+	}
+	input := UpdateRepositoryInput{
+		RepositoryID:          githubv4.ID(id),
+		Description:           githubv4.NewString(githubv4.String(data.Description)),
+		HasDiscussionsEnabled: githubv4.NewBoolean(githubv4.Boolean(data.HasDiscussions)),
+		HasIssuesEnabled:      githubv4.NewBoolean(githubv4.Boolean(data.HasIssues)),
+		HasProjectsEnabled:    githubv4.NewBoolean(githubv4.Boolean(data.HasProjects)),
+		HasWikiEnabled:        githubv4.NewBoolean(githubv4.Boolean(data.HasWiki)),
+		HomepageURL:           githubv4.NewString(githubv4.String(data.HomepageURL)),
+		Name:                  githubv4.NewString(githubv4.String(data.Name)),
+		Template:              githubv4.NewBoolean(githubv4.Boolean(data.IsTemplate)),
+	}
+
+	client := meta.(*Organization).Client
+	return client.Mutate(ctx, &mutate, input, nil)
+}
+
+// updateRepositorySettings applies the settings updateRepository does not
+// expose (visibility, merge behaviour and the default branch) through REST.
+func updateRepositorySettings(ctx context.Context, data RepositoryResourceData, meta interface{}) error {
+	body := map[string]interface{}{
+		"visibility":             strings.ToLower(data.Visibility),
+		"allow_auto_merge":       data.AllowAutoMerge,
+		"allow_merge_commit":     data.AllowMergeCommit,
+		"allow_rebase_merge":     data.AllowRebaseMerge,
+		"allow_squash_merge":     data.AllowSquashMerge,
+		"delete_branch_on_merge": data.DeleteBranchOnMerge,
+	}
+	if data.DefaultBranch != "" {
+		body["default_branch"] = data.DefaultBranch
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s", meta.(*Organization).Name, data.Name)
+	_, err := restRequest(ctx, meta, "PATCH", path, body, nil)
+
+	return err
+}
+
+func setRepositoryArchived(ctx context.Context, id string, archived bool, meta interface{}) error {
+	client := meta.(*Organization).Client
+
+	if archived {
+		var mutate struct {
+			ArchiveRepository struct {
+				Repository struct {
+					ID githubv4.ID
+				}
+			} `graphql:"archiveRepository(input: $input)"`
+		}
+		input := githubv4.ArchiveRepositoryInput{
+			RepositoryID: githubv4.ID(id),
+		}
+		return client.Mutate(ctx, &mutate, input, nil)
+	}
+
+	var mutate struct {
+		UnarchiveRepository struct {
+			Repository struct {
+				ID githubv4.ID
+			}
+		} `graphql:"unarchiveRepository(input: $input)"`
+	}
+	input := UnarchiveRepositoryInput{
+		RepositoryID: githubv4.ID(id),
+	}
+	return client.Mutate(ctx, &mutate, input, nil)
+}
@@ -0,0 +1,298 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceGithubRepositoryCollaborator() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node ID of the repository.",
+			},
+			USER_LOGIN: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+			},
+			USER_PERMISSION: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     string(githubv4.RepositoryPermissionWrite),
+				Description: "READ, TRIAGE, WRITE, MAINTAIN, ADMIN or the name of a custom repository role.",
+			},
+			// Computed
+			INVITATION_ID: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			MEMBERSHIP_STATE: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Create: resourceGithubRepositoryCollaboratorCreate,
+		Read:   resourceGithubRepositoryCollaboratorRead,
+		Update: resourceGithubRepositoryCollaboratorUpdate,
+		Delete: resourceGithubRepositoryCollaboratorDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRepositoryCollaboratorImport,
+		},
+	}
+}
+
+func resourceGithubRepositoryCollaboratorCreate(d *schema.ResourceData, meta interface{}) error {
+	repositoryID := d.Get(REPOSITORY_ID).(string)
+	login := d.Get(USER_LOGIN).(string)
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	nameWithOwner, err := getRepositoryNameWithOwner(ctx, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	// GraphQL has no mutations for collaborators. Users who are not yet
+	// collaborators are sent an invitation, which is followed until accepted.
+	err = setRepositoryCollaborator(ctx, nameWithOwner, login, d.Get(USER_PERMISSION).(string), meta)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", repositoryID, login))
+
+	return resourceGithubRepositoryCollaboratorRead(d, meta)
+}
+
+func resourceGithubRepositoryCollaboratorRead(d *schema.ResourceData, meta interface{}) error {
+	repositoryID, login, err := parseRepositoryCollaboratorID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	nameWithOwner, err := getRepositoryNameWithOwner(ctx, repositoryID, meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing repository collaborator (%s) from state because the repository no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	state := MEMBERSHIP_STATE_ACTIVE
+	permission := ""
+	invitationID := 0
+
+	path := fmt.Sprintf("/repos/%s/collaborators/%s", nameWithOwner, login)
+	status, err := restRequest(ctx, meta, "GET", path, nil, nil)
+	if err != nil && status != http.StatusNotFound {
+		return err
+	}
+
+	if status == http.StatusNotFound {
+		invitation, err := getRepositoryInvitation(ctx, nameWithOwner, login, meta)
+		if err != nil {
+			return err
+		}
+		if invitation == nil {
+			log.Printf("[WARN] Removing repository collaborator (%s) from state because they are no longer a collaborator or invited", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		state = MEMBERSHIP_STATE_PENDING
+		permission = repositoryRoleNamePermission(invitation.Permissions)
+		invitationID = invitation.ID
+	} else {
+		var collaborator struct {
+			RoleName string `json:"role_name"`
+		}
+		_, err = restRequest(ctx, meta, "GET", fmt.Sprintf("%s/permission", path), nil, &collaborator)
+		if err != nil {
+			return err
+		}
+		permission = repositoryRoleNamePermission(collaborator.RoleName)
+	}
+
+	err = d.Set(REPOSITORY_ID, repositoryID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s collaborator (%s)", REPOSITORY_ID, nameWithOwner, d.Id())
+	}
+
+	err = d.Set(USER_LOGIN, login)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s collaborator (%s)", USER_LOGIN, nameWithOwner, d.Id())
+	}
+
+	err = d.Set(USER_PERMISSION, permission)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s collaborator (%s)", USER_PERMISSION, nameWithOwner, d.Id())
+	}
+
+	err = d.Set(MEMBERSHIP_STATE, state)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s collaborator (%s)", MEMBERSHIP_STATE, nameWithOwner, d.Id())
+	}
+
+	err = d.Set(INVITATION_ID, invitationID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s collaborator (%s)", INVITATION_ID, nameWithOwner, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubRepositoryCollaboratorUpdate(d *schema.ResourceData, meta interface{}) error {
+	repositoryID, login, err := parseRepositoryCollaboratorID(d.Id())
+	if err != nil {
+		return err
+	}
+	permission := d.Get(USER_PERMISSION).(string)
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	nameWithOwner, err := getRepositoryNameWithOwner(ctx, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	if d.Get(MEMBERSHIP_STATE).(string) == MEMBERSHIP_STATE_PENDING {
+		// The invitation endpoint names the built-in roles after the
+		// permission rather than the git operation.
+		path := fmt.Sprintf("/repos/%s/invitations/%d", nameWithOwner, d.Get(INVITATION_ID).(int))
+		body := map[string]interface{}{
+			"permissions": permission,
+		}
+		if _, ok := repositoryRestPermissions[permission]; ok {
+			body["permissions"] = strings.ToLower(permission)
+		}
+		_, err = restRequest(ctx, meta, "PATCH", path, body, nil)
+	} else {
+		err = setRepositoryCollaborator(ctx, nameWithOwner, login, permission, meta)
+	}
+	if err != nil {
+		return err
+	}
+
+	return resourceGithubRepositoryCollaboratorRead(d, meta)
+}
+
+func resourceGithubRepositoryCollaboratorDelete(d *schema.ResourceData, meta interface{}) error {
+	repositoryID, login, err := parseRepositoryCollaboratorID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	nameWithOwner, err := getRepositoryNameWithOwner(ctx, repositoryID, meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			return nil
+		}
+
+		return err
+	}
+
+	path := fmt.Sprintf("/repos/%s/collaborators/%s", nameWithOwner, login)
+	if d.Get(MEMBERSHIP_STATE).(string) == MEMBERSHIP_STATE_PENDING {
+		path = fmt.Sprintf("/repos/%s/invitations/%d", nameWithOwner, d.Get(INVITATION_ID).(int))
+	}
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// resourceGithubRepositoryCollaboratorImport accepts `repository:login`.
+func resourceGithubRepositoryCollaboratorImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("error invalid import ID %q, expected <repository>:<login>", d.Id())
+	}
+
+	repositoryID, err := getRepositoryID(parts[0], meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", repositoryID, parts[1]))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseRepositoryCollaboratorID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("error unexpected repository collaborator ID %q", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func setRepositoryCollaborator(ctx context.Context, nameWithOwner string, login string, permission string, meta interface{}) error {
+	path := fmt.Sprintf("/repos/%s/collaborators/%s", nameWithOwner, login)
+	body := map[string]interface{}{
+		"permission": repositoryRestPermission(permission),
+	}
+	_, err := restRequest(ctx, meta, "PUT", path, body, nil)
+
+	return err
+}
+
+// getRepositoryInvitation returns the pending invitation for login, or nil
+// if there is none.
+func getRepositoryInvitation(ctx context.Context, nameWithOwner string, login string, meta interface{}) (*RepositoryInvitation, error) {
+	for page := 1; ; page++ {
+		var invitations []RepositoryInvitation
+		path := fmt.Sprintf("/repos/%s/invitations?per_page=100&page=%d", nameWithOwner, page)
+		_, err := restRequest(ctx, meta, "GET", path, nil, &invitations)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range invitations {
+			if strings.EqualFold(invitations[i].Invitee.Login, login) {
+				return &invitations[i], nil
+			}
+		}
+
+		if len(invitations) < 100 {
+			return nil, nil
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strings"
+	"time"
+)
+
+func resourceGithubRepositoryFile() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node ID of the repository.",
+			},
+			FILE_PATH: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The path of the file within the repository.",
+			},
+			FILE_CONTENT: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The text of the file; binary files are not supported.",
+			},
+			COMMIT_BRANCH: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The branch to commit to; defaults to the default branch.",
+			},
+			COMMIT_MESSAGE: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "{action} {path}",
+				Description: "The commit message; {action}, {branch}, {path} and {repository} are replaced.",
+			},
+			COMMIT_AUTHOR: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Credited through a Co-authored-by trailer; requires commit_email.",
+			},
+			COMMIT_EMAIL: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The email for the Co-authored-by trailer; requires commit_author.",
+			},
+			FILE_OVERWRITE_ON_CREATE: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Replace a file which already exists when the resource is created.",
+			},
+			// Computed
+			COMMIT_SHA: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			FILE_SHA: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Create: resourceGithubRepositoryFileCreate,
+		Read:   resourceGithubRepositoryFileRead,
+		Update: resourceGithubRepositoryFileUpdate,
+		Delete: resourceGithubRepositoryFileDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRepositoryFileImport,
+		},
+	}
+}
+
+func resourceGithubRepositoryFileCreate(d *schema.ResourceData, meta interface{}) error {
+	err := checkCommitAuthor(d)
+	if err != nil {
+		return err
+	}
+
+	repositoryID := d.Get(REPOSITORY_ID).(string)
+	path := d.Get(FILE_PATH).(string)
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	branch := d.Get(COMMIT_BRANCH).(string)
+	if branch == "" {
+		branch, err = getRepositoryDefaultBranch(ctx, repositoryID, meta)
+		if err != nil {
+			return err
+		}
+		if branch == "" {
+			return fmt.Errorf("error repository %s has no default branch to commit %s to", repositoryID, path)
+		}
+	}
+
+	_, oid, err := getFileContent(ctx, repositoryID, branch, path, meta)
+	if err != nil {
+		return err
+	}
+	if oid != "" && !d.Get(FILE_OVERWRITE_ON_CREATE).(bool) {
+		return fmt.Errorf("error %s already exists on %s; set %s to replace it", path, branch, FILE_OVERWRITE_ON_CREATE)
+	}
+
+	action := "Create"
+	if oid != "" {
+		action = "Update"
+	}
+	content := d.Get(FILE_CONTENT).(string)
+	err = commitRepositoryFile(ctx, d, repositoryID, branch, path, action, &content, oid, meta)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", repositoryID, branch, path))
+
+	return resourceGithubRepositoryFileRead(d, meta)
+}
+
+func resourceGithubRepositoryFileRead(d *schema.ResourceData, meta interface{}) error {
+	repositoryID, branch, path, err := parseRepositoryFileID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	content, oid, err := getFileContent(ctx, repositoryID, branch, path, meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing repository file (%s) from state because the repository no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+	if oid == "" {
+		log.Printf("[WARN] Removing repository file (%s) from state because it no longer exists on %s", d.Id(), branch)
+		d.SetId("")
+		return nil
+	}
+
+	err = d.Set(REPOSITORY_ID, repositoryID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in repository file (%s)", REPOSITORY_ID, d.Id())
+	}
+
+	err = d.Set(COMMIT_BRANCH, branch)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in repository file (%s)", COMMIT_BRANCH, d.Id())
+	}
+
+	err = d.Set(FILE_PATH, path)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in repository file (%s)", FILE_PATH, d.Id())
+	}
+
+	err = d.Set(FILE_CONTENT, content)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in repository file (%s)", FILE_CONTENT, d.Id())
+	}
+
+	err = d.Set(FILE_SHA, oid)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in repository file (%s)", FILE_SHA, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubRepositoryFileUpdate(d *schema.ResourceData, meta interface{}) error {
+	err := checkCommitAuthor(d)
+	if err != nil {
+		return err
+	}
+
+	// Only the content warrants a commit; the commit settings apply to the
+	// next one.
+	if !d.HasChange(FILE_CONTENT) {
+		return resourceGithubRepositoryFileRead(d, meta)
+	}
+
+	repositoryID, branch, path, err := parseRepositoryFileID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	sha, _ := d.GetChange(FILE_SHA)
+	content := d.Get(FILE_CONTENT).(string)
+	err = commitRepositoryFile(ctx, d, repositoryID, branch, path, "Update", &content, sha.(string), meta)
+	if err != nil {
+		return err
+	}
+
+	return resourceGithubRepositoryFileRead(d, meta)
+}
+
+func resourceGithubRepositoryFileDelete(d *schema.ResourceData, meta interface{}) error {
+	repositoryID, branch, path, err := parseRepositoryFileID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	_, oid, err := getFileContent(ctx, repositoryID, branch, path, meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			return nil
+		}
+
+		return err
+	}
+	if oid == "" {
+		return nil
+	}
+
+	return commitRepositoryFile(ctx, d, repositoryID, branch, path, "Delete", nil, d.Get(FILE_SHA).(string), meta)
+}
+
+// resourceGithubRepositoryFileImport accepts `repository:branch:path`.
+func resourceGithubRepositoryFileImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("error invalid import ID %q, expected <repository>:<branch>:<path>", d.Id())
+	}
+
+	repositoryID, err := getRepositoryID(parts[0], meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", repositoryID, parts[1], parts[2]))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Git does not allow ':' in branch names, so the path may contain it.
+func parseRepositoryFileID(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("error unexpected repository file ID %q", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// checkCommitAuthor fails if only half of the Co-authored-by trailer is
+// configured, rather than dropping the credit without a word.
+func checkCommitAuthor(d *schema.ResourceData) error {
+	author := d.Get(COMMIT_AUTHOR).(string)
+	email := d.Get(COMMIT_EMAIL).(string)
+	if (author == "") != (email == "") {
+		return fmt.Errorf("error %s and %s must be set together", COMMIT_AUTHOR, COMMIT_EMAIL)
+	}
+	return nil
+}
+
+// commitRepositoryFile writes content to path, or deletes it when content is
+// nil, in a single commit on top of the branch's current head. The file at
+// that head must still be the blob expectedSha, empty if it should not
+// exist, so changes made outside Terraform are never overwritten; the head is
+// in turn passed as the expected head oid, so the commit fails if anything
+// lands in between.
+func commitRepositoryFile(ctx context.Context, d *schema.ResourceData, repositoryID string, branch string, path string, action string, content *string, expectedSha string, meta interface{}) error {
+	nameWithOwner, err := getRepositoryNameWithOwner(ctx, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	ref, err := getBranchRef(ctx, repositoryID, branch, meta)
+	if err != nil {
+		return err
+	}
+	if ref.ID == nil {
+		return fmt.Errorf("error branch %s does not exist in %s", branch, nameWithOwner)
+	}
+
+	_, oid, err := getFileContent(ctx, repositoryID, string(ref.Target.Oid), path, meta)
+	if err != nil {
+		return err
+	}
+	if oid != expectedSha {
+		return fmt.Errorf("error %s on %s has changed since it was last read; refresh to pick up the change before applying", path, branch)
+	}
+
+	var additions map[string]string
+	var deletions []string
+	if content != nil {
+		additions = map[string]string{path: *content}
+	} else {
+		deletions = []string{path}
+	}
+
+	message := renderCommitMessage(d.Get(COMMIT_MESSAGE).(string), action, nameWithOwner, branch, path)
+	body := commitAuthorTrailer(d.Get(COMMIT_AUTHOR).(string), d.Get(COMMIT_EMAIL).(string))
+
+	sha, err := commitOnBranch(ctx, nameWithOwner, branch, ref.Target.Oid, message, body, additions, deletions, meta)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(COMMIT_SHA, sha)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in repository file (%s)", COMMIT_SHA, d.Id())
+	}
+
+	return nil
+}
@@ -0,0 +1,490 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"strings"
+	"time"
+)
+
+func resourceGithubRepositoryRuleset() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node ID of the repository.",
+			},
+			RULESET_NAME: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			RULESET_TARGET: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "BRANCH",
+				ValidateFunc: validation.StringInSlice([]string{
+					"BRANCH",
+					"TAG",
+				}, false),
+			},
+			RULESET_ENFORCEMENT: {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"ACTIVE",
+					"DISABLED",
+					"EVALUATE",
+				}, false),
+				Description: "EVALUATE reports rule violations without blocking them.",
+			},
+			RULESET_CONDITIONS: {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						RULESET_REF_NAME: {
+							Type:     schema.TypeList,
+							Required: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									RULESET_INCLUDE: {
+										Type:        schema.TypeList,
+										Required:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Description: "Ref names or fnmatch patterns; ~DEFAULT_BRANCH and ~ALL are also accepted.",
+									},
+									RULESET_EXCLUDE: {
+										Type:     schema.TypeList,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			RULESET_BYPASS_ACTOR: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     rulesetBypassActorResource(),
+			},
+			RULESET_RULES: {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						RULE_CREATION: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						RULE_UPDATE: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						RULE_UPDATE_ALLOWS_FETCH_AND_MERGE: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Only valid together with update.",
+						},
+						RULE_DELETION: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						RULE_NON_FAST_FORWARD: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						RULE_REQUIRED_LINEAR_HISTORY: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						RULE_REQUIRED_SIGNATURES: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						RULE_PULL_REQUEST: {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT: {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										Default:      0,
+										ValidateFunc: validation.IntBetween(0, 10),
+									},
+									RULE_DISMISS_STALE_REVIEWS_ON_PUSH: {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+									},
+									RULE_REQUIRE_CODE_OWNER_REVIEW: {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+									},
+									PROTECTION_REQUIRE_LAST_PUSH_APPROVAL: {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+									},
+									RULE_REQUIRED_REVIEW_THREAD_RESOLUTION: {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+									},
+								},
+							},
+						},
+						RULE_REQUIRED_STATUS_CHECKS: {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									RULE_REQUIRED_CHECK: {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												PROTECTION_STATUS_CHECK_CONTEXT: {
+													Type:     schema.TypeString,
+													Required: true,
+												},
+												RULE_INTEGRATION_ID: {
+													Type:        schema.TypeInt,
+													Optional:    true,
+													Description: "The ID of the GitHub App that must set the status. Any source is accepted when unset.",
+												},
+											},
+										},
+									},
+									RULE_STRICT_REQUIRED_STATUS_CHECKS_POLICY: {
+										Type:     schema.TypeBool,
+										Optional: true,
+										Default:  false,
+									},
+								},
+							},
+						},
+						RULE_REQUIRED_DEPLOYMENTS: {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									RULE_REQUIRED_DEPLOYMENT_ENVIRONMENTS: {
+										Type:     schema.TypeList,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						RULE_BRANCH_NAME_PATTERN:         rulePatternSchema(),
+						RULE_COMMIT_AUTHOR_EMAIL_PATTERN: rulePatternSchema(),
+						RULE_COMMIT_MESSAGE_PATTERN:      rulePatternSchema(),
+						RULE_COMMITTER_EMAIL_PATTERN:     rulePatternSchema(),
+						RULE_TAG_NAME_PATTERN:            rulePatternSchema(),
+					},
+				},
+			},
+		},
+
+		Create: resourceGithubRepositoryRulesetCreate,
+		Read:   resourceGithubRepositoryRulesetRead,
+		Update: resourceGithubRepositoryRulesetUpdate,
+		Delete: resourceGithubRepositoryRulesetDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRepositoryRulesetImport,
+		},
+	}
+}
+
+func rulesetBypassActorResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			ACTOR_APP: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The slug of a GitHub App.",
+			},
+			ACTOR_TEAM: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The slug of a team in the organization.",
+			},
+			RULESET_REPOSITORY_ROLE_ID: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The database ID of a repository role.",
+			},
+			RULESET_ORGANIZATION_ADMIN: {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			RULESET_DEPLOY_KEY: {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			RULESET_BYPASS_MODE: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "ALWAYS",
+				ValidateFunc: validation.StringInSlice([]string{
+					"ALWAYS",
+					"PULL_REQUEST",
+				}, false),
+			},
+			ACTOR_ID: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ACTOR_TYPE: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func rulePatternSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				RULE_PATTERN_NAME: {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				RULE_PATTERN_NEGATE: {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				RULE_PATTERN_OPERATOR: {
+					Type:     schema.TypeString,
+					Required: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"contains",
+						"ends_with",
+						"regex",
+						"starts_with",
+					}, false),
+				},
+				RULE_PATTERN: {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+	}
+}
+
+func resourceGithubRepositoryRulesetCreate(d *schema.ResourceData, meta interface{}) error {
+	var mutate struct {
+		CreateRepositoryRuleset struct {
+			Ruleset struct {
+				ID githubv4.ID
+			}
+		} `graphql:"createRepositoryRuleset(input: $input)"`
+	}
+	data, err := newRepositoryRulesetInput(d, meta)
+	if err != nil {
+		return err
+	}
+	input := CreateRepositoryRulesetInput{
+		SourceID:               githubv4.ID(d.Get(REPOSITORY_ID).(string)),
+		repositoryRulesetInput: data,
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	client := meta.(*Organization).Client
+	err = client.Mutate(ctx, &mutate, input, nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s", mutate.CreateRepositoryRuleset.Ruleset.ID))
+
+	return resourceGithubRepositoryRulesetRead(d, meta)
+}
+
+func resourceGithubRepositoryRulesetRead(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	ruleset, err := getRepositoryRuleset(ctx, d.Id(), meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing repository ruleset (%s) from state because it no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	err = checkRulesetRules(ruleset)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(REPOSITORY_ID, ruleset.Source.Repository.ID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", REPOSITORY_ID, ruleset.Name, d.Id())
+	}
+
+	err = d.Set(RULESET_NAME, string(ruleset.Name))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", RULESET_NAME, ruleset.Name, d.Id())
+	}
+
+	err = d.Set(RULESET_TARGET, string(ruleset.Target))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", RULESET_TARGET, ruleset.Name, d.Id())
+	}
+
+	err = d.Set(RULESET_ENFORCEMENT, string(ruleset.Enforcement))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", RULESET_ENFORCEMENT, ruleset.Name, d.Id())
+	}
+
+	err = d.Set(RULESET_CONDITIONS, flattenRulesetConditions(ruleset))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", RULESET_CONDITIONS, ruleset.Name, d.Id())
+	}
+
+	err = d.Set(RULESET_BYPASS_ACTOR, flattenRulesetBypassActors(ruleset.BypassActors.Nodes))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", RULESET_BYPASS_ACTOR, ruleset.Name, d.Id())
+	}
+
+	err = d.Set(RULESET_RULES, flattenRulesetRules(ruleset))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s repository ruleset (%s)", RULESET_RULES, ruleset.Name, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubRepositoryRulesetUpdate(d *schema.ResourceData, meta interface{}) error {
+	var mutate struct {
+		UpdateRepositoryRuleset struct {
+			Ruleset struct {
+				ID githubv4.ID
+			}
+		} `graphql:"updateRepositoryRuleset(input: $input)"`
+	}
+	data, err := newRepositoryRulesetInput(d, meta)
+	if err != nil {
+		return err
+	}
+	input := UpdateRepositoryRulesetInput{
+		RepositoryRulesetID:    d.Id(),
+		repositoryRulesetInput: data,
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	// State may predate a rule added outside Terraform, which the update
+	// would otherwise delete.
+	ruleset, err := getRepositoryRuleset(ctx, d.Id(), meta)
+	if err != nil {
+		return err
+	}
+	err = checkRulesetRules(ruleset)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*Organization).Client
+	err = client.Mutate(ctx, &mutate, input, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceGithubRepositoryRulesetRead(d, meta)
+}
+
+func resourceGithubRepositoryRulesetDelete(d *schema.ResourceData, meta interface{}) error {
+	var mutate struct {
+		DeleteRepositoryRuleset struct { // Empty struct does not work
+			ClientMutationId githubv4.ID
+		} `graphql:"deleteRepositoryRuleset(input: $input)"`
+	}
+	input := DeleteRepositoryRulesetInput{
+		RepositoryRulesetID: d.Id(),
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	client := meta.(*Organization).Client
+	err := client.Mutate(ctx, &mutate, input, nil)
+	if err != nil && strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+		return nil
+	}
+
+	return err
+}
+
+// resourceGithubRepositoryRulesetImport accepts either a RepositoryRuleset
+// node ID or `repository:name`.
+func resourceGithubRepositoryRulesetImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 {
+		return []*schema.ResourceData{d}, nil
+	}
+	if parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("error invalid import ID %q, expected <repository>:<name>", d.Id())
+	}
+
+	repositoryID, err := getRepositoryID(parts[0], meta)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := getRepositoryRulesetID(meta.(*Organization).StopContext, repositoryID, parts[1], meta)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, fmt.Errorf("error no ruleset named %q in %s", parts[1], parts[0])
+	}
+
+	d.SetId(fmt.Sprintf("%s", id))
+
+	return []*schema.ResourceData{d}, nil
+}
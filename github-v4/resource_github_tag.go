@@ -0,0 +1,31 @@
+package github
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"time"
+)
+
+// Tags are created lightweight, pointing straight at the source commit.
+func resourceGithubTag() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: refSchema(REF_TAG, "The name of the tag, without refs/tags/."),
+
+		Create: resourceGithubRefCreate(REF_NAMESPACE_TAG, REF_TAG),
+		Read:   resourceGithubRefRead(REF_NAMESPACE_TAG, REF_TAG),
+		Update: resourceGithubRefUpdate(REF_NAMESPACE_TAG, REF_TAG),
+		Delete: resourceGithubRefDelete(REF_NAMESPACE_TAG),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubRefImport,
+		},
+	}
+}
@@ -0,0 +1,338 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceGithubTeam() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			TEAM_NAME: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "",
+			},
+			TEAM_DESCRIPTION: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			TEAM_PRIVACY: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(githubv4.TeamPrivacySecret),
+				ValidateFunc: validation.StringInSlice([]string{
+					string(githubv4.TeamPrivacySecret),
+					string(githubv4.TeamPrivacyVisible),
+				}, false),
+			},
+			TEAM_PARENT_TEAM_ID: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{TEAM_PARENT_TEAM_SLUG},
+				Description:   "The node ID of the parent team.",
+			},
+			TEAM_PARENT_TEAM_SLUG: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{TEAM_PARENT_TEAM_ID},
+				Description:   "The slug of the parent team.",
+			},
+			TEAM_NOTIFICATION_SETTING: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "NOTIFICATIONS_ENABLED",
+				ValidateFunc: validation.StringInSlice([]string{
+					"NOTIFICATIONS_ENABLED",
+					"NOTIFICATIONS_DISABLED",
+				}, false),
+			},
+			TEAM_REVIEW_REQUEST_DELEGATION: {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						TEAM_REVIEW_ALGORITHM: {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "ROUND_ROBIN",
+							ValidateFunc: validation.StringInSlice([]string{
+								"ROUND_ROBIN",
+								"LOAD_BALANCE",
+							}, false),
+						},
+						TEAM_REVIEW_MEMBER_COUNT: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						TEAM_REVIEW_NOTIFY_TEAM: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			// Computed
+			TEAM_SLUG: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Create: resourceGithubTeamCreate,
+		Read:   resourceGithubTeamRead,
+		Update: resourceGithubTeamUpdate,
+		Delete: resourceGithubTeamDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubTeamImport,
+		},
+	}
+}
+
+func resourceGithubTeamCreate(d *schema.ResourceData, meta interface{}) error {
+	data, err := teamResourceData(d)
+	if err != nil {
+		return err
+	}
+
+	body, err := teamRestBody(data, meta)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	// GraphQL has no mutation to create or edit a team.
+	var team struct {
+		NodeID string `json:"node_id"`
+	}
+	path := fmt.Sprintf("/orgs/%s/teams", meta.(*Organization).Name)
+	_, err = restRequest(ctx, meta, "POST", path, body, &team)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(team.NodeID)
+
+	if data.ReviewRequestDelegation {
+		err = updateTeamReviewAssignment(ctx, d.Id(), data, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceGithubTeamRead(d, meta)
+}
+
+func resourceGithubTeamRead(d *schema.ResourceData, meta interface{}) error {
+	var query struct {
+		Node struct {
+			Team Team `graphql:"... on Team"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":              githubv4.ID(d.Id()),
+		"childTeamFirst":  githubv4.Int(1),
+		"childTeamCursor": (*githubv4.String)(nil),
+		"immediateOnly":   githubv4.Boolean(true),
+		"membersFirst":    githubv4.Int(1),
+		"membersCursor":   (*githubv4.String)(nil),
+		"membership":      githubv4.TeamMembershipTypeImmediate,
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing team (%s) from state because it no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	team := query.Node.Team
+
+	err = d.Set(TEAM_NAME, team.Name)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_NAME, team.Slug, d.Id())
+	}
+
+	err = d.Set(TEAM_SLUG, team.Slug)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_SLUG, team.Slug, d.Id())
+	}
+
+	err = d.Set(TEAM_DESCRIPTION, team.Description)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_DESCRIPTION, team.Slug, d.Id())
+	}
+
+	err = d.Set(TEAM_PRIVACY, team.Privacy)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_PRIVACY, team.Slug, d.Id())
+	}
+
+	err = d.Set(TEAM_NOTIFICATION_SETTING, team.NotificationSetting)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_NOTIFICATION_SETTING, team.Slug, d.Id())
+	}
+
+	// The parent is read back in whichever form it was declared, defaulting
+	// to the slug (e.g. on import).
+	if d.Get(TEAM_PARENT_TEAM_ID).(string) != "" {
+		parentTeamID := ""
+		if team.ParentTeam.ID != nil {
+			parentTeamID = fmt.Sprintf("%s", team.ParentTeam.ID)
+		}
+		err = d.Set(TEAM_PARENT_TEAM_ID, parentTeamID)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_PARENT_TEAM_ID, team.Slug, d.Id())
+		}
+	} else {
+		err = d.Set(TEAM_PARENT_TEAM_SLUG, team.ParentTeam.Slug)
+		if err != nil {
+			log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_PARENT_TEAM_SLUG, team.Slug, d.Id())
+		}
+	}
+
+	var reviewRequestDelegation []interface{}
+	if team.ReviewRequestDelegationEnabled {
+		reviewRequestDelegation = []interface{}{
+			map[string]interface{}{
+				TEAM_REVIEW_ALGORITHM:    string(team.ReviewRequestDelegationAlgorithm),
+				TEAM_REVIEW_MEMBER_COUNT: int(team.ReviewRequestDelegationMemberCount),
+				TEAM_REVIEW_NOTIFY_TEAM:  bool(team.ReviewRequestDelegationNotifyTeam),
+			},
+		}
+	}
+	err = d.Set(TEAM_REVIEW_REQUEST_DELEGATION, reviewRequestDelegation)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team (%s)", TEAM_REVIEW_REQUEST_DELEGATION, team.Slug, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubTeamUpdate(d *schema.ResourceData, meta interface{}) error {
+	data, err := teamResourceData(d)
+	if err != nil {
+		return err
+	}
+
+	body, err := teamRestBody(data, meta)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	// The slug in state is the one the team had before any rename.
+	path := fmt.Sprintf("/orgs/%s/teams/%s", meta.(*Organization).Name, d.Get(TEAM_SLUG).(string))
+	_, err = restRequest(ctx, meta, "PATCH", path, body, nil)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange(TEAM_REVIEW_REQUEST_DELEGATION) {
+		err = updateTeamReviewAssignment(ctx, d.Id(), data, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceGithubTeamRead(d, meta)
+}
+
+func resourceGithubTeamDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	path := fmt.Sprintf("/orgs/%s/teams/%s", meta.(*Organization).Name, d.Get(TEAM_SLUG).(string))
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+func resourceGithubTeamImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := getTeamID(d.Id(), meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s", id))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func teamRestBody(data TeamResourceData, meta interface{}) (map[string]interface{}, error) {
+	body := map[string]interface{}{
+		"name":                 data.Name,
+		"description":          data.Description,
+		"privacy":              teamRestPrivacy(data.Privacy),
+		"notification_setting": strings.ToLower(data.NotificationSetting),
+		"parent_team_id":       nil,
+	}
+
+	if data.ParentTeamID != "" || data.ParentTeamSlug != "" {
+		parentTeamID, err := getTeamDatabaseID(data.ParentTeamSlug, data.ParentTeamID, meta)
+		if err != nil {
+			return nil, err
+		}
+		body["parent_team_id"] = parentTeamID
+	}
+
+	return body, nil
+}
+
+func updateTeamReviewAssignment(ctx context.Context, id string, data TeamResourceData, meta interface{}) error {
+	var mutate struct {
+		UpdateTeamReviewAssignment struct {
+			Team struct {
+				ID githubv4.ID
+			}
+		} `graphql:"updateTeamReviewAssignment(input: $input)"`
+	}
+	input := UpdateTeamReviewAssignmentInput{
+		ID:      githubv4.ID(id),
+		Enabled: githubv4.Boolean(data.ReviewRequestDelegation),
+	}
+	if data.ReviewRequestDelegation {
+		input.Algorithm = githubv4.NewString(githubv4.String(data.ReviewAlgorithm))
+		input.NotifyTeam = githubv4.NewBoolean(githubv4.Boolean(data.ReviewNotifyTeam))
+		input.TeamMemberCount = githubv4.NewInt(githubv4.Int(data.ReviewMemberCount))
+	}
+
+	client := meta.(*Organization).Client
+	return client.Mutate(ctx, &mutate, input, nil)
+}
@@ -0,0 +1,300 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceGithubTeamMembers() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			TEAM_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node ID of the team.",
+			},
+			TEAM_MEMBERS_IGNORE_CHILD_TEAM: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Only manage immediate members, ignoring those inherited from child teams. Otherwise inherited members must be listed with their current role, as they can only be changed in the child team.",
+			},
+			TEAM_MEMBER: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						USER_LOGIN: {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						USER_ROLE: {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  string(githubv4.TeamMemberRoleMember),
+							ValidateFunc: validation.StringInSlice([]string{
+								string(githubv4.TeamMemberRoleMember),
+								string(githubv4.TeamMemberRoleMaintainer),
+							}, false),
+						},
+					},
+				},
+			},
+			// Computed
+			TEAM_SLUG: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Create: resourceGithubTeamMembersCreateOrUpdate,
+		Read:   resourceGithubTeamMembersRead,
+		Update: resourceGithubTeamMembersCreateOrUpdate,
+		Delete: resourceGithubTeamMembersDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubTeamMembersImport,
+		},
+	}
+}
+
+func resourceGithubTeamMembersCreateOrUpdate(d *schema.ResourceData, meta interface{}) error {
+	timeout := schema.TimeoutUpdate
+	if d.IsNewResource() {
+		timeout = schema.TimeoutCreate
+	}
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(timeout))
+	defer cancel()
+
+	// Only immediate members can be changed here. Members inherited from
+	// child teams are read-only: DELETE cannot remove them and PUT would
+	// make them immediate members.
+	teamID := d.Get(TEAM_ID).(string)
+	slug, current, err := getTeamMembersAndInvitations(ctx, teamID, githubv4.TeamMembershipTypeImmediate, meta)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]string, len(current))
+	for _, m := range current {
+		existing[strings.ToLower(string(m.Node.Login))] = string(m.Role)
+	}
+
+	inherited := make(map[string]string)
+	if teamMembershipType(d) == githubv4.TeamMembershipTypeAll {
+		_, all, err := getTeamMembers(ctx, teamID, githubv4.TeamMembershipTypeAll, meta)
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			login := strings.ToLower(string(m.Node.Login))
+			if _, ok := existing[login]; !ok {
+				inherited[login] = string(m.Role)
+			}
+		}
+	}
+
+	desired := expandTeamMembers(d)
+	for login, role := range desired {
+		if r, ok := inherited[strings.ToLower(login)]; ok && r != role {
+			return fmt.Errorf("error %s is a %s of team %s through a child team; change their role in that team", login, r, slug)
+		}
+	}
+	for login := range inherited {
+		if _, ok := desiredLogin(desired, login); !ok {
+			return fmt.Errorf("error %s is a member of team %s through a child team and cannot be removed here; add them to %s or set %s", login, slug, TEAM_MEMBER, TEAM_MEMBERS_IGNORE_CHILD_TEAM)
+		}
+	}
+
+	// Additions and role changes are the same REST call.
+	for login, role := range desired {
+		if _, ok := inherited[strings.ToLower(login)]; ok {
+			continue
+		}
+		if r, ok := existing[strings.ToLower(login)]; ok && r == role {
+			continue
+		}
+		log.Printf("[DEBUG] Setting %s as %s of team %s", login, role, slug)
+		err = setTeamMembership(ctx, slug, login, role, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range current {
+		login := string(m.Node.Login)
+		if _, ok := desiredLogin(desired, login); ok {
+			continue
+		}
+		log.Printf("[DEBUG] Removing %s from team %s", login, slug)
+		err = removeTeamMembership(ctx, slug, login, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId(teamID)
+
+	return resourceGithubTeamMembersRead(d, meta)
+}
+
+func resourceGithubTeamMembersRead(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	slug, current, err := getTeamMembersAndInvitations(ctx, d.Id(), teamMembershipType(d), meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing team members (%s) from state because the team no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	// Logins are case-insensitive; keep the casing used in configuration so
+	// it does not show up as a change.
+	desired := expandTeamMembers(d)
+	members := make([]interface{}, 0, len(current))
+	for _, m := range current {
+		login := string(m.Node.Login)
+		if l, ok := desiredLogin(desired, login); ok {
+			login = l
+		}
+		members = append(members, map[string]interface{}{
+			USER_LOGIN: login,
+			USER_ROLE:  string(m.Role),
+		})
+	}
+
+	err = d.Set(TEAM_ID, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team members (%s)", TEAM_ID, slug, d.Id())
+	}
+
+	err = d.Set(TEAM_SLUG, slug)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team members (%s)", TEAM_SLUG, slug, d.Id())
+	}
+
+	err = d.Set(TEAM_MEMBER, members)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in %s team members (%s)", TEAM_MEMBER, slug, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubTeamMembersDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	slug, current, err := getTeamMembersAndInvitations(ctx, d.Id(), githubv4.TeamMembershipTypeImmediate, meta)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range current {
+		err = removeTeamMembership(ctx, slug, string(m.Node.Login), meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceGithubTeamMembersImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := getTeamID(d.Id(), meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s", id))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// getTeamMembersAndInvitations is getTeamMembers plus anyone with a pending
+// invitation to the team. They count as members: adding them again would
+// only resend the invitation, and removing them cancels it.
+func getTeamMembersAndInvitations(ctx context.Context, id string, membership githubv4.TeamMembershipType, meta interface{}) (string, []TeamMember, error) {
+	slug, members, err := getTeamMembers(ctx, id, membership, meta)
+	if err != nil {
+		return "", nil, err
+	}
+
+	invitations, err := getTeamInvitations(ctx, slug, meta)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return slug, append(members, invitations...), nil
+}
+
+func teamMembershipType(d *schema.ResourceData) githubv4.TeamMembershipType {
+	if d.Get(TEAM_MEMBERS_IGNORE_CHILD_TEAM).(bool) {
+		return githubv4.TeamMembershipTypeImmediate
+	}
+	return githubv4.TeamMembershipTypeAll
+}
+
+func expandTeamMembers(d *schema.ResourceData) map[string]string {
+	members := make(map[string]string)
+	if v, ok := d.GetOk(TEAM_MEMBER); ok {
+		for _, v := range v.(*schema.Set).List() {
+			m := v.(map[string]interface{})
+			members[m[USER_LOGIN].(string)] = m[USER_ROLE].(string)
+		}
+	}
+	return members
+}
+
+func desiredLogin(desired map[string]string, login string) (string, bool) {
+	for l := range desired {
+		if strings.EqualFold(l, login) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// GraphQL has no mutations for team membership.
+func setTeamMembership(ctx context.Context, slug string, login string, role string, meta interface{}) error {
+	path := fmt.Sprintf("/orgs/%s/teams/%s/memberships/%s", meta.(*Organization).Name, slug, login)
+	body := map[string]interface{}{
+		"role": strings.ToLower(role),
+	}
+	_, err := restRequest(ctx, meta, "PUT", path, body, nil)
+
+	return err
+}
+
+func removeTeamMembership(ctx context.Context, slug string, login string, meta interface{}) error {
+	path := fmt.Sprintf("/orgs/%s/teams/%s/memberships/%s", meta.(*Organization).Name, slug, login)
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
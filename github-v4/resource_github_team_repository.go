@@ -0,0 +1,283 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/shurcooL/githubv4"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceGithubTeamRepository() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			// Input
+			TEAM_ID: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{TEAM_SLUG},
+				Description:   "The node ID of the team.",
+			},
+			TEAM_SLUG: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{TEAM_ID},
+				Description:   "The slug of the team.",
+			},
+			REPOSITORY_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node ID of the repository.",
+			},
+			TEAM_PERMISSION: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     string(githubv4.RepositoryPermissionRead),
+				Description: "READ, TRIAGE, WRITE, MAINTAIN, ADMIN or the name of a custom repository role.",
+			},
+		},
+
+		Create: resourceGithubTeamRepositoryCreate,
+		Read:   resourceGithubTeamRepositoryRead,
+		Update: resourceGithubTeamRepositoryUpdate,
+		Delete: resourceGithubTeamRepositoryDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGithubTeamRepositoryImport,
+		},
+	}
+}
+
+func resourceGithubTeamRepositoryCreate(d *schema.ResourceData, meta interface{}) error {
+	teamID := d.Get(TEAM_ID).(string)
+	if teamID == "" {
+		slug, ok := d.GetOk(TEAM_SLUG)
+		if !ok {
+			return fmt.Errorf("error one of %s or %s must be set", TEAM_ID, TEAM_SLUG)
+		}
+		id, err := getTeamID(slug.(string), meta)
+		if err != nil {
+			return err
+		}
+		teamID = fmt.Sprintf("%s", id)
+	}
+	repositoryID := d.Get(REPOSITORY_ID).(string)
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	_, path, err := teamRepositoryPath(ctx, teamID, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	err = setTeamRepositoryPermission(ctx, path, d.Get(TEAM_PERMISSION).(string), meta)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", teamID, repositoryID))
+
+	return resourceGithubTeamRepositoryRead(d, meta)
+}
+
+func resourceGithubTeamRepositoryRead(d *schema.ResourceData, meta interface{}) error {
+	teamID, repositoryID, err := parseTeamRepositoryID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	slug, path, err := teamRepositoryPath(ctx, teamID, repositoryID, meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			log.Printf("[WARN] Removing team repository (%s) from state because the team or repository no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	// Only the repository media type reports the role, including custom
+	// roles; the default one answers 204 with no body.
+	var repository struct {
+		RoleName string `json:"role_name"`
+	}
+	status, err := restRequestMediaType(ctx, meta, "application/vnd.github.v3.repository+json", "GET", path, nil, &repository)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Removing team repository (%s) from state because the team no longer has access", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = d.Set(TEAM_ID, teamID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in team repository (%s)", TEAM_ID, d.Id())
+	}
+
+	err = d.Set(TEAM_SLUG, slug)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in team repository (%s)", TEAM_SLUG, d.Id())
+	}
+
+	err = d.Set(REPOSITORY_ID, repositoryID)
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in team repository (%s)", REPOSITORY_ID, d.Id())
+	}
+
+	err = d.Set(TEAM_PERMISSION, repositoryRoleNamePermission(repository.RoleName))
+	if err != nil {
+		log.Printf("[WARN] Problem setting '%s' in team repository (%s)", TEAM_PERMISSION, d.Id())
+	}
+
+	return nil
+}
+
+func resourceGithubTeamRepositoryUpdate(d *schema.ResourceData, meta interface{}) error {
+	teamID, repositoryID, err := parseTeamRepositoryID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	_, path, err := teamRepositoryPath(ctx, teamID, repositoryID, meta)
+	if err != nil {
+		return err
+	}
+
+	err = setTeamRepositoryPermission(ctx, path, d.Get(TEAM_PERMISSION).(string), meta)
+	if err != nil {
+		return err
+	}
+
+	return resourceGithubTeamRepositoryRead(d, meta)
+}
+
+func resourceGithubTeamRepositoryDelete(d *schema.ResourceData, meta interface{}) error {
+	teamID, repositoryID, err := parseTeamRepositoryID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(meta.(*Organization).StopContext, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	_, path, err := teamRepositoryPath(ctx, teamID, repositoryID, meta)
+	if err != nil {
+		if strings.Contains(err.Error(), "Could not resolve to a node with the global id") {
+			return nil
+		}
+
+		return err
+	}
+
+	status, err := restRequest(ctx, meta, "DELETE", path, nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// resourceGithubTeamRepositoryImport accepts `team-slug:repository`.
+func resourceGithubTeamRepositoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("error invalid import ID %q, expected <team-slug>:<repository>", d.Id())
+	}
+
+	teamID, err := getTeamID(parts[0], meta)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryID, err := getRepositoryID(parts[1], meta)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", teamID, repositoryID))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseTeamRepositoryID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("error unexpected team repository ID %q", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// teamRepositoryPath resolves the current team slug and repository name, so
+// renames of either do not break the grant. GraphQL has no mutations for
+// team repository access.
+func teamRepositoryPath(ctx context.Context, teamID string, repositoryID string, meta interface{}) (string, string, error) {
+	var query struct {
+		Team struct {
+			Team struct {
+				Slug githubv4.String
+			} `graphql:"... on Team"`
+		} `graphql:"team: node(id: $teamId)"`
+		Repository struct {
+			Repository struct {
+				Name  githubv4.String
+				Owner struct {
+					Login githubv4.String
+				}
+			} `graphql:"... on Repository"`
+		} `graphql:"repository: node(id: $repositoryId)"`
+	}
+	variables := map[string]interface{}{
+		"teamId":       githubv4.ID(teamID),
+		"repositoryId": githubv4.ID(repositoryID),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", "", err
+	}
+
+	slug := string(query.Team.Team.Slug)
+	return slug, fmt.Sprintf("/orgs/%s/teams/%s/repos/%s/%s",
+		meta.(*Organization).Name,
+		slug,
+		query.Repository.Repository.Owner.Login,
+		query.Repository.Repository.Name,
+	), nil
+}
+
+func setTeamRepositoryPermission(ctx context.Context, path string, permission string, meta interface{}) error {
+	body := map[string]interface{}{
+		"permission": repositoryRestPermission(permission),
+	}
+	_, err := restRequest(ctx, meta, "PUT", path, body, nil)
+
+	return err
+}
@@ -1,6 +1,9 @@
 package github
 
-import "github.com/hashicorp/terraform/helper/schema"
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
 
 func resourceGithubBranchProtectionV0() *schema.Resource {
 	return &schema.Resource{
@@ -27,7 +30,7 @@ func resourceGithubBranchProtectionUpgradeV0(rawState map[string]interface{}, me
 	}
 
 	branch := rawState["branch"].(string)
-	branchProtectionRuleID, err := getBranchProtectionID(repositoryName, branch, meta)
+	branchProtectionRuleID, err := getBranchProtectionID(meta.(*Organization).Name, repositoryName, branch, meta)
 	if err != nil {
 		return nil, err
 	}
@@ -38,3 +41,274 @@ func resourceGithubBranchProtectionUpgradeV0(rawState map[string]interface{}, me
 
 	return rawState, nil
 }
+
+func resourceGithubBranchProtectionV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			REPOSITORY_ID: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			PROTECTION_PATTERN: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			PROTECTION_IS_ADMIN_ENFORCED: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_COMMIT_SIGNATURES: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_APPROVING_REVIEWS: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntBetween(1, 6),
+						},
+						PROTECTION_REQUIRES_CODE_OWNER_REVIEWS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_DISMISSES_STALE_REVIEWS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_RESTRICTS_REVIEW_DISMISSALS: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			PROTECTION_REQUIRES_STATUS_CHECKS: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						PROTECTION_REQUIRES_STRICT_STATUS_CHECKS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			PROTECTION_RESTRICTS_PUSHES: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceGithubBranchProtectionUpgradeV1(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if v, ok := rawState[PROTECTION_RESTRICTS_PUSHES]; ok {
+		pushAllowances, err := getActors(rawStateStringSlice(v), meta)
+		if err != nil {
+			return nil, err
+		}
+		rawState[PROTECTION_PUSH_ALLOWANCES] = pushAllowances
+		delete(rawState, PROTECTION_RESTRICTS_PUSHES)
+	}
+
+	if v, ok := rawState[PROTECTION_REQUIRES_APPROVING_REVIEWS].([]interface{}); ok {
+		for _, v := range v {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if v, ok := m[PROTECTION_RESTRICTS_REVIEW_DISMISSALS]; ok {
+				dismissalAllowances, err := getActors(rawStateStringSlice(v), meta)
+				if err != nil {
+					return nil, err
+				}
+				m[PROTECTION_REVIEW_DISMISSAL_ALLOWANCES] = dismissalAllowances
+				delete(m, PROTECTION_RESTRICTS_REVIEW_DISMISSALS)
+			}
+		}
+	}
+
+	return rawState, nil
+}
+
+func resourceGithubBranchProtectionV2() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			// Input
+			REPOSITORY_ID: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "",
+			},
+			PROTECTION_PATTERN: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "",
+			},
+			PROTECTION_IS_ADMIN_ENFORCED: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_COMMIT_SIGNATURES: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_LINEAR_HISTORY: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_CONVERSATION_RESOLUTION: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_ALLOWS_DELETIONS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_ALLOWS_FORCE_PUSHES: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_BLOCKS_CREATIONS: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_LOCK_BRANCH: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			PROTECTION_REQUIRES_APPROVING_REVIEWS: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntBetween(1, 6),
+						},
+						PROTECTION_REQUIRES_CODE_OWNER_REVIEWS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_REQUIRE_LAST_PUSH_APPROVAL: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_DISMISSES_STALE_REVIEWS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_RESTRICT_DISMISSALS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+						},
+						PROTECTION_REVIEW_DISMISSAL_ALLOWANCES: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     branchProtectionActorResource(),
+						},
+						PROTECTION_BYPASS_PULL_REQUEST_ALLOWANCES: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     branchProtectionActorResource(),
+						},
+					},
+				},
+			},
+			PROTECTION_REQUIRES_STATUS_CHECKS: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						PROTECTION_REQUIRES_STRICT_STATUS_CHECKS: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			PROTECTION_PUSH_ALLOWANCES: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     branchProtectionActorResource(),
+			},
+			PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     branchProtectionActorResource(),
+			},
+		},
+	}
+}
+
+func resourceGithubBranchProtectionUpgradeV2(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if v, ok := rawState[PROTECTION_REQUIRES_STATUS_CHECKS].([]interface{}); ok {
+		for _, v := range v {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if v, ok := m[PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS]; ok {
+				checks := make([]interface{}, 0)
+				for _, c := range rawStateStringSlice(v) {
+					checks = append(checks, map[string]interface{}{
+						PROTECTION_STATUS_CHECK_CONTEXT: c,
+						PROTECTION_STATUS_CHECK_APP_ID:  "",
+					})
+				}
+				m[PROTECTION_REQUIRED_STATUS_CHECKS] = checks
+				delete(m, PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS)
+			}
+		}
+	}
+
+	return rawState, nil
+}
+
+func rawStateStringSlice(v interface{}) []string {
+	res := make([]string, 0)
+	if vL, ok := v.([]interface{}); ok {
+		for _, v := range vL {
+			res = append(res, v.(string))
+		}
+	}
+	return res
+}
@@ -3,6 +3,8 @@ package github
 import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
+	"net/url"
+	"time"
 )
 
 type PageInfo struct {
@@ -40,3 +42,19 @@ func githubv4IDSlice(ss []string) []githubv4.ID {
 func githubv4NewStringSlice(v []githubv4.String) *[]githubv4.String { return &v }
 
 func githubv4NewIDSlice(v []githubv4.ID) *[]githubv4.ID { return &v }
+
+func githubv4URI(s string) (*githubv4.URI, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &githubv4.URI{URL: u}, nil
+}
+
+// formatDateTime renders a timestamp as RFC 3339, or "" if it was null.
+func formatDateTime(t githubv4.DateTime) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
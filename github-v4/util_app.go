@@ -0,0 +1,26 @@
+package github
+
+import (
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"net/http"
+)
+
+// getAppID looks up a GitHub App by slug. GraphQL has no root field for
+// Apps, so the node ID is taken from the REST representation.
+func getAppID(slug string, meta interface{}) (githubv4.ID, error) {
+	var app struct {
+		NodeID string `json:"node_id"`
+	}
+
+	ctx := meta.(*Organization).StopContext
+	status, err := restRequest(ctx, meta, "GET", fmt.Sprintf("/apps/%s", slug), nil, &app)
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("error app %s not found", slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return githubv4.ID(app.NodeID), nil
+}
@@ -8,23 +8,75 @@ import (
 )
 
 const (
-	PROTECTION_DISMISSES_STALE_REVIEWS         = "dismiss_stale_reviews"
-	PROTECTION_IS_ADMIN_ENFORCED               = "enforce_admins"
-	PROTECTION_PATTERN                         = "pattern"
-	PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT = "required_approving_review_count"
-	PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS  = "contexts"
-	PROTECTION_REQUIRES_APPROVING_REVIEWS      = "required_pull_request_reviews"
-	PROTECTION_REQUIRES_CODE_OWNER_REVIEWS     = "require_code_owner_reviews"
-	PROTECTION_REQUIRES_COMMIT_SIGNATURES      = "require_signed_commits"
-	PROTECTION_REQUIRES_STATUS_CHECKS          = "required_status_checks"
-	PROTECTION_REQUIRES_STRICT_STATUS_CHECKS   = "strict"
-	PROTECTION_RESTRICTS_PUSHES                = "push_restrictions"
-	PROTECTION_RESTRICTS_REVIEW_DISMISSALS     = "dismissal_restrictions"
+	ACTOR_APP  = "app"
+	ACTOR_ID   = "actor_id"
+	ACTOR_TEAM = "team"
+	ACTOR_TYPE = "actor_type"
+	ACTOR_USER = "user"
+
+	PROTECTION_ALLOWS_DELETIONS                 = "allows_deletions"
+	PROTECTION_ALLOWS_FORCE_PUSHES              = "allows_force_pushes"
+	PROTECTION_BLOCKS_CREATIONS                 = "blocks_creations"
+	PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES     = "force_push_bypass_allowance"
+	PROTECTION_BYPASS_PULL_REQUEST_ALLOWANCES   = "pull_request_bypass_allowance"
+	PROTECTION_DISMISSES_STALE_REVIEWS          = "dismiss_stale_reviews"
+	PROTECTION_IS_ADMIN_ENFORCED                = "enforce_admins"
+	PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE      = "lock_allows_fetch_and_merge"
+	PROTECTION_LOCK_BRANCH                      = "lock_branch"
+	PROTECTION_MATCHING_REFS                    = "matching_refs"
+	PROTECTION_PATTERN                          = "pattern"
+	PROTECTION_PUSH_ALLOWANCES                  = "push_allowance"
+	PROTECTION_REQUIRE_LAST_PUSH_APPROVAL       = "require_last_push_approval"
+	PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT  = "required_approving_review_count"
+	PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS   = "contexts"
+	PROTECTION_REQUIRED_STATUS_CHECKS           = "check"
+	PROTECTION_REQUIRES_APPROVING_REVIEWS       = "required_pull_request_reviews"
+	PROTECTION_REQUIRES_CODE_OWNER_REVIEWS      = "require_code_owner_reviews"
+	PROTECTION_REQUIRES_COMMIT_SIGNATURES       = "require_signed_commits"
+	PROTECTION_REQUIRES_CONVERSATION_RESOLUTION = "require_conversation_resolution"
+	PROTECTION_REQUIRES_LINEAR_HISTORY          = "require_linear_history"
+	PROTECTION_REQUIRES_STATUS_CHECKS           = "required_status_checks"
+	PROTECTION_REQUIRES_STRICT_STATUS_CHECKS    = "strict"
+	PROTECTION_RESTRICT_DISMISSALS              = "restrict_dismissals"
+	PROTECTION_RESTRICTS_PUSHES                 = "push_restrictions"
+	PROTECTION_RESTRICTS_REVIEW_DISMISSALS      = "dismissal_restrictions"
+	PROTECTION_RULE_ID                          = "rule_id"
+	PROTECTION_RULES                            = "rules"
+	PROTECTION_REVIEW_DISMISSAL_ALLOWANCES      = "dismissal_allowance"
+	PROTECTION_STATUS_CHECK_APP_ID              = "app_id"
+	PROTECTION_STATUS_CHECK_CONTEXT             = "context"
+
+	// Sent in place of an App ID to accept a status check from any source.
+	STATUS_CHECK_ANY_APP = "any"
 )
 
+// Actor is a member of the BranchActorAllowanceActor union. Inline fragments
+// share the JSON object they are decoded from, so every fragment receives the
+// `id` field; Typename is the only reliable way to tell them apart.
 type Actor struct {
-	ID   githubv4.ID
-	Name githubv4.String
+	Typename githubv4.String `graphql:"__typename"`
+	App      struct {
+		ID   githubv4.ID
+		Slug githubv4.String
+	} `graphql:"... on App"`
+	Team struct {
+		ID   githubv4.ID
+		Slug githubv4.String
+	} `graphql:"... on Team"`
+	User struct {
+		ID    githubv4.ID
+		Login githubv4.String
+	} `graphql:"... on User"`
+}
+
+type Allowance struct {
+	Actor Actor
+}
+
+type ActorResourceData struct {
+	App  string
+	Team string
+	User string
 }
 
 type BranchProtectionRule struct {
@@ -32,56 +84,153 @@ type BranchProtectionRule struct {
 		ID   githubv4.String
 		Name githubv4.String
 	}
+	BypassForcePushAllowances struct {
+		Nodes    []Allowance
+		PageInfo PageInfo
+	} `graphql:"bypassForcePushAllowances(first: $bypassForcePushFirst, after: $bypassForcePushCursor)"`
+	BypassPullRequestAllowances struct {
+		Nodes    []Allowance
+		PageInfo PageInfo
+	} `graphql:"bypassPullRequestAllowances(first: $bypassPullRequestFirst, after: $bypassPullRequestCursor)"`
 	PushAllowances struct {
-		Nodes []struct {
-			Actor struct {
-				// `App` is not supported (at least for GitHub App Installation tokens)
-				// Seem to be unable to provide the necessary permissions.
-				Team Actor `graphql:"... on Team"`
-				User Actor `graphql:"... on User"`
-			}
-		}
-	} `graphql:"pushAllowances(first: 100)"`
+		Nodes    []Allowance
+		PageInfo PageInfo
+	} `graphql:"pushAllowances(first: $pushFirst, after: $pushCursor)"`
 	ReviewDismissalAllowances struct {
-		Nodes []struct {
-			Actor struct {
-				Team Actor `graphql:"... on Team"`
-				User Actor `graphql:"... on User"`
-			}
-		}
-	} `graphql:"reviewDismissalAllowances(first: 100)"`
-	DismissesStaleReviews        githubv4.Boolean
-	ID                           githubv4.ID
-	IsAdminEnforced              githubv4.Boolean
-	Pattern                      githubv4.String
-	RequiredApprovingReviewCount githubv4.Int
-	RequiredStatusCheckContexts  []githubv4.String
-	RequiresApprovingReviews     githubv4.Boolean
-	RequiresCodeOwnerReviews     githubv4.Boolean
-	RequiresCommitSignatures     githubv4.Boolean
-	RequiresStatusChecks         githubv4.Boolean
-	RequiresStrictStatusChecks   githubv4.Boolean
-	RestrictsPushes              githubv4.Boolean
-	RestrictsReviewDismissals    githubv4.Boolean
+		Nodes    []Allowance
+		PageInfo PageInfo
+	} `graphql:"reviewDismissalAllowances(first: $dismissalFirst, after: $dismissalCursor)"`
+	AllowsDeletions                githubv4.Boolean
+	AllowsForcePushes              githubv4.Boolean
+	BlocksCreations                githubv4.Boolean
+	DismissesStaleReviews          githubv4.Boolean
+	ID                             githubv4.ID
+	IsAdminEnforced                githubv4.Boolean
+	LockAllowsFetchAndMerge        githubv4.Boolean
+	LockBranch                     githubv4.Boolean
+	Pattern                        githubv4.String
+	RequireLastPushApproval        githubv4.Boolean
+	RequiredApprovingReviewCount   githubv4.Int
+	RequiredStatusChecks           []RequiredStatusCheck
+	RequiresApprovingReviews       githubv4.Boolean
+	RequiresCodeOwnerReviews       githubv4.Boolean
+	RequiresCommitSignatures       githubv4.Boolean
+	RequiresConversationResolution githubv4.Boolean
+	RequiresLinearHistory          githubv4.Boolean
+	RequiresStatusChecks           githubv4.Boolean
+	RequiresStrictStatusChecks     githubv4.Boolean
+	RestrictsPushes                githubv4.Boolean
+	RestrictsReviewDismissals      githubv4.Boolean
 }
 
 type BranchProtectionResourceData struct {
-	BranchProtectionRuleID       string
-	DismissesStaleReviews        bool
-	IsAdminEnforced              bool
-	Pattern                      string
-	PushActorIDs                 []string
-	RepositoryID                 string
-	RequiredApprovingReviewCount int
-	RequiredStatusCheckContexts  []string
-	RequiresApprovingReviews     bool
-	RequiresCodeOwnerReviews     bool
-	RequiresCommitSignatures     bool
-	RequiresStatusChecks         bool
-	RequiresStrictStatusChecks   bool
-	RestrictsPushes              bool
-	RestrictsReviewDismissals    bool
-	ReviewDismissalActorIDs      []string
+	AllowsDeletions                bool
+	AllowsForcePushes              bool
+	BlocksCreations                bool
+	BranchProtectionRuleID         string
+	BypassForcePushActorIDs        []string
+	BypassPullRequestActorIDs      []string
+	DismissesStaleReviews          bool
+	IsAdminEnforced                bool
+	LockAllowsFetchAndMerge        bool
+	LockBranch                     bool
+	Pattern                        string
+	PushActorIDs                   []string
+	RepositoryID                   string
+	RequireLastPushApproval        bool
+	RequiredApprovingReviewCount   int
+	RequiredStatusChecks           []RequiredStatusCheckInput
+	RequiresApprovingReviews       bool
+	RequiresCodeOwnerReviews       bool
+	RequiresCommitSignatures       bool
+	RequiresConversationResolution bool
+	RequiresLinearHistory          bool
+	RequiresStatusChecks           bool
+	RequiresStrictStatusChecks     bool
+	RestrictsPushes                bool
+	RestrictsReviewDismissals      bool
+	ReviewDismissalActorIDs        []string
+}
+
+// The vendored githubv4 predates most branch protection settings, so the
+// mutation inputs are declared here. The GraphQL input type is derived from
+// the Go type name.
+type CreateBranchProtectionRuleInput struct {
+	RepositoryID githubv4.ID     `json:"repositoryId"`
+	Pattern      githubv4.String `json:"pattern"`
+	branchProtectionRuleInput
+}
+
+type UpdateBranchProtectionRuleInput struct {
+	BranchProtectionRuleID githubv4.ID      `json:"branchProtectionRuleId"`
+	Pattern                *githubv4.String `json:"pattern,omitempty"`
+	branchProtectionRuleInput
+}
+
+type branchProtectionRuleInput struct {
+	AllowsDeletions                *githubv4.Boolean           `json:"allowsDeletions,omitempty"`
+	AllowsForcePushes              *githubv4.Boolean           `json:"allowsForcePushes,omitempty"`
+	BlocksCreations                *githubv4.Boolean           `json:"blocksCreations,omitempty"`
+	BypassForcePushActorIDs        *[]githubv4.ID              `json:"bypassForcePushActorIds,omitempty"`
+	BypassPullRequestActorIDs      *[]githubv4.ID              `json:"bypassPullRequestActorIds,omitempty"`
+	DismissesStaleReviews          *githubv4.Boolean           `json:"dismissesStaleReviews,omitempty"`
+	IsAdminEnforced                *githubv4.Boolean           `json:"isAdminEnforced,omitempty"`
+	LockAllowsFetchAndMerge        *githubv4.Boolean           `json:"lockAllowsFetchAndMerge,omitempty"`
+	LockBranch                     *githubv4.Boolean           `json:"lockBranch,omitempty"`
+	PushActorIDs                   *[]githubv4.ID              `json:"pushActorIds,omitempty"`
+	RequireLastPushApproval        *githubv4.Boolean           `json:"requireLastPushApproval,omitempty"`
+	RequiredApprovingReviewCount   *githubv4.Int               `json:"requiredApprovingReviewCount,omitempty"`
+	RequiredStatusChecks           *[]RequiredStatusCheckInput `json:"requiredStatusChecks,omitempty"`
+	RequiresApprovingReviews       *githubv4.Boolean           `json:"requiresApprovingReviews,omitempty"`
+	RequiresCodeOwnerReviews       *githubv4.Boolean           `json:"requiresCodeOwnerReviews,omitempty"`
+	RequiresCommitSignatures       *githubv4.Boolean           `json:"requiresCommitSignatures,omitempty"`
+	RequiresConversationResolution *githubv4.Boolean           `json:"requiresConversationResolution,omitempty"`
+	RequiresLinearHistory          *githubv4.Boolean           `json:"requiresLinearHistory,omitempty"`
+	RequiresStatusChecks           *githubv4.Boolean           `json:"requiresStatusChecks,omitempty"`
+	RequiresStrictStatusChecks     *githubv4.Boolean           `json:"requiresStrictStatusChecks,omitempty"`
+	RestrictsPushes                *githubv4.Boolean           `json:"restrictsPushes,omitempty"`
+	RestrictsReviewDismissals      *githubv4.Boolean           `json:"restrictsReviewDismissals,omitempty"`
+	ReviewDismissalActorIDs        *[]githubv4.ID              `json:"reviewDismissalActorIds,omitempty"`
+}
+
+type RequiredStatusCheck struct {
+	App struct {
+		ID githubv4.ID
+	}
+	Context githubv4.String
+}
+
+type RequiredStatusCheckInput struct {
+	AppID   githubv4.ID     `json:"appId,omitempty"`
+	Context githubv4.String `json:"context"`
+}
+
+func newBranchProtectionRuleInput(data BranchProtectionResourceData) branchProtectionRuleInput {
+	return branchProtectionRuleInput{
+		AllowsDeletions:                githubv4.NewBoolean(githubv4.Boolean(data.AllowsDeletions)),
+		AllowsForcePushes:              githubv4.NewBoolean(githubv4.Boolean(data.AllowsForcePushes)),
+		BlocksCreations:                githubv4.NewBoolean(githubv4.Boolean(data.BlocksCreations)),
+		BypassForcePushActorIDs:        githubv4NewIDSlice(githubv4IDSlice(data.BypassForcePushActorIDs)),
+		BypassPullRequestActorIDs:      githubv4NewIDSlice(githubv4IDSlice(data.BypassPullRequestActorIDs)),
+		DismissesStaleReviews:          githubv4.NewBoolean(githubv4.Boolean(data.DismissesStaleReviews)),
+		IsAdminEnforced:                githubv4.NewBoolean(githubv4.Boolean(data.IsAdminEnforced)),
+		LockAllowsFetchAndMerge:        githubv4.NewBoolean(githubv4.Boolean(data.LockAllowsFetchAndMerge)),
+		LockBranch:                     githubv4.NewBoolean(githubv4.Boolean(data.LockBranch)),
+		PushActorIDs:                   githubv4NewIDSlice(githubv4IDSlice(data.PushActorIDs)),
+		RequireLastPushApproval:        githubv4.NewBoolean(githubv4.Boolean(data.RequireLastPushApproval)),
+		RequiredApprovingReviewCount:   githubv4.NewInt(githubv4.Int(data.RequiredApprovingReviewCount)),
+		RequiredStatusChecks:           &data.RequiredStatusChecks,
+		RequiresApprovingReviews:       githubv4.NewBoolean(githubv4.Boolean(data.RequiresApprovingReviews)),
+		RequiresCodeOwnerReviews:       githubv4.NewBoolean(githubv4.Boolean(data.RequiresCodeOwnerReviews)),
+		RequiresCommitSignatures:       githubv4.NewBoolean(githubv4.Boolean(data.RequiresCommitSignatures)),
+		RequiresConversationResolution: githubv4.NewBoolean(githubv4.Boolean(data.RequiresConversationResolution)),
+		RequiresLinearHistory:          githubv4.NewBoolean(githubv4.Boolean(data.RequiresLinearHistory)),
+		RequiresStatusChecks:           githubv4.NewBoolean(githubv4.Boolean(data.RequiresStatusChecks)),
+		RequiresStrictStatusChecks:     githubv4.NewBoolean(githubv4.Boolean(data.RequiresStrictStatusChecks)),
+		RestrictsPushes:                githubv4.NewBoolean(githubv4.Boolean(data.RestrictsPushes)),
+		RestrictsReviewDismissals:      githubv4.NewBoolean(githubv4.Boolean(data.RestrictsReviewDismissals)),
+		ReviewDismissalActorIDs:        githubv4NewIDSlice(githubv4IDSlice(data.ReviewDismissalActorIDs)),
+	}
 }
 
 func branchProtectionResourceData(d *schema.ResourceData, meta interface{}) (BranchProtectionResourceData, error) {
@@ -103,6 +252,34 @@ func branchProtectionResourceData(d *schema.ResourceData, meta interface{}) (Bra
 		data.RequiresCommitSignatures = v.(bool)
 	}
 
+	if v, ok := d.GetOk(PROTECTION_REQUIRES_LINEAR_HISTORY); ok {
+		data.RequiresLinearHistory = v.(bool)
+	}
+
+	if v, ok := d.GetOk(PROTECTION_REQUIRES_CONVERSATION_RESOLUTION); ok {
+		data.RequiresConversationResolution = v.(bool)
+	}
+
+	if v, ok := d.GetOk(PROTECTION_ALLOWS_DELETIONS); ok {
+		data.AllowsDeletions = v.(bool)
+	}
+
+	if v, ok := d.GetOk(PROTECTION_ALLOWS_FORCE_PUSHES); ok {
+		data.AllowsForcePushes = v.(bool)
+	}
+
+	if v, ok := d.GetOk(PROTECTION_BLOCKS_CREATIONS); ok {
+		data.BlocksCreations = v.(bool)
+	}
+
+	if v, ok := d.GetOk(PROTECTION_LOCK_BRANCH); ok {
+		data.LockBranch = v.(bool)
+	}
+
+	if v, ok := d.GetOk(PROTECTION_LOCK_ALLOWS_FETCH_AND_MERGE); ok {
+		data.LockAllowsFetchAndMerge = v.(bool)
+	}
+
 	if v, ok := d.GetOk(PROTECTION_REQUIRES_APPROVING_REVIEWS); ok {
 		vL := v.([]interface{})
 		if len(vL) > 1 {
@@ -126,11 +303,23 @@ func branchProtectionResourceData(d *schema.ResourceData, meta interface{}) (Bra
 			if v, ok := m[PROTECTION_REQUIRES_CODE_OWNER_REVIEWS]; ok {
 				data.RequiresCodeOwnerReviews = v.(bool)
 			}
-			if v, ok := m[PROTECTION_RESTRICTS_REVIEW_DISMISSALS]; ok {
-				reviewDismissalActorIDs := make([]string, 0)
-				vL := v.(*schema.Set).List()
-				for _, v := range vL {
-					reviewDismissalActorIDs = append(reviewDismissalActorIDs, v.(string))
+			if v, ok := m[PROTECTION_REQUIRE_LAST_PUSH_APPROVAL]; ok {
+				data.RequireLastPushApproval = v.(bool)
+			}
+			if v, ok := m[PROTECTION_RESTRICT_DISMISSALS]; ok {
+				data.RestrictsReviewDismissals = v.(bool)
+			}
+			if v, ok := m[PROTECTION_BYPASS_PULL_REQUEST_ALLOWANCES]; ok {
+				bypassPullRequestActorIDs, err := resolveActorIDs(v.(*schema.Set).List(), meta)
+				if err != nil {
+					return BranchProtectionResourceData{}, err
+				}
+				data.BypassPullRequestActorIDs = bypassPullRequestActorIDs
+			}
+			if v, ok := m[PROTECTION_REVIEW_DISMISSAL_ALLOWANCES]; ok {
+				reviewDismissalActorIDs, err := resolveActorIDs(v.(*schema.Set).List(), meta)
+				if err != nil {
+					return BranchProtectionResourceData{}, err
 				}
 				if len(reviewDismissalActorIDs) > 0 {
 					data.ReviewDismissalActorIDs = reviewDismissalActorIDs
@@ -156,18 +345,17 @@ func branchProtectionResourceData(d *schema.ResourceData, meta interface{}) (Bra
 				data.RequiresStrictStatusChecks = v.(bool)
 			}
 
-			data.RequiredStatusCheckContexts = expandNestedSet(m, PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS)
-			if len(data.RequiredStatusCheckContexts) > 0 {
+			data.RequiredStatusChecks = expandStatusChecks(m)
+			if len(data.RequiredStatusChecks) > 0 {
 				data.RequiresStatusChecks = true
 			}
 		}
 	}
 
-	if v, ok := d.GetOk(PROTECTION_RESTRICTS_PUSHES); ok {
-		pushActorIDs := make([]string, 0)
-		vL := v.(*schema.Set).List()
-		for _, v := range vL {
-			pushActorIDs = append(pushActorIDs, v.(string))
+	if v, ok := d.GetOk(PROTECTION_PUSH_ALLOWANCES); ok {
+		pushActorIDs, err := resolveActorIDs(v.(*schema.Set).List(), meta)
+		if err != nil {
+			return BranchProtectionResourceData{}, err
 		}
 		if len(pushActorIDs) > 0 {
 			data.PushActorIDs = pushActorIDs
@@ -175,6 +363,14 @@ func branchProtectionResourceData(d *schema.ResourceData, meta interface{}) (Bra
 		}
 	}
 
+	if v, ok := d.GetOk(PROTECTION_BYPASS_FORCE_PUSH_ALLOWANCES); ok {
+		bypassForcePushActorIDs, err := resolveActorIDs(v.(*schema.Set).List(), meta)
+		if err != nil {
+			return BranchProtectionResourceData{}, err
+		}
+		data.BypassForcePushActorIDs = bypassForcePushActorIDs
+	}
+
 	return data, nil
 }
 
@@ -183,23 +379,15 @@ func setApprovingReviews(protection BranchProtectionRule) interface{} {
 		return nil
 	}
 
-	dismissalAllowances := protection.ReviewDismissalAllowances.Nodes
-	dismissalActors := make([]interface{}, 0, len(dismissalAllowances))
-	for _, d := range dismissalAllowances {
-		if d.Actor.Team != (Actor{}) {
-			dismissalActors = append(dismissalActors, d.Actor.Team.ID)
-		}
-		if d.Actor.User != (Actor{}) {
-			dismissalActors = append(dismissalActors, d.Actor.Team.ID)
-		}
-	}
-
 	approvalReviews := []interface{}{
 		map[string]interface{}{
 			PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT: protection.RequiredApprovingReviewCount,
 			PROTECTION_REQUIRES_CODE_OWNER_REVIEWS:     protection.RequiresCodeOwnerReviews,
+			PROTECTION_REQUIRE_LAST_PUSH_APPROVAL:      protection.RequireLastPushApproval,
 			PROTECTION_DISMISSES_STALE_REVIEWS:         protection.DismissesStaleReviews,
-			PROTECTION_RESTRICTS_REVIEW_DISMISSALS:     dismissalActors,
+			PROTECTION_RESTRICT_DISMISSALS:             protection.RestrictsReviewDismissals,
+			PROTECTION_REVIEW_DISMISSAL_ALLOWANCES:     flattenAllowances(protection.ReviewDismissalAllowances.Nodes),
+			PROTECTION_BYPASS_PULL_REQUEST_ALLOWANCES:  flattenAllowances(protection.BypassPullRequestAllowances.Nodes),
 		},
 	}
 
@@ -213,34 +401,243 @@ func setStatusChecks(protection BranchProtectionRule) interface{} {
 
 	statusChecks := []interface{}{
 		map[string]interface{}{
-			PROTECTION_REQUIRES_STRICT_STATUS_CHECKS:  protection.RequiresStrictStatusChecks,
-			PROTECTION_REQUIRED_STATUS_CHECK_CONTEXTS: protection.RequiredStatusCheckContexts,
+			PROTECTION_REQUIRES_STRICT_STATUS_CHECKS: protection.RequiresStrictStatusChecks,
+			PROTECTION_REQUIRED_STATUS_CHECKS:        flattenStatusChecks(protection),
 		},
 	}
 
 	return statusChecks
 }
 
-func setPushes(protection BranchProtectionRule) []string {
+func expandStatusChecks(m map[string]interface{}) []RequiredStatusCheckInput {
+	checks := make([]RequiredStatusCheckInput, 0)
+	if v, ok := m[PROTECTION_REQUIRED_STATUS_CHECKS]; ok {
+		for _, v := range v.(*schema.Set).List() {
+			c := v.(map[string]interface{})
+			appID := c[PROTECTION_STATUS_CHECK_APP_ID].(string)
+			if appID == "" {
+				appID = STATUS_CHECK_ANY_APP
+			}
+			checks = append(checks, RequiredStatusCheckInput{
+				AppID:   githubv4.ID(appID),
+				Context: githubv4.String(c[PROTECTION_STATUS_CHECK_CONTEXT].(string)),
+			})
+		}
+	}
+	return checks
+}
+
+func flattenStatusChecks(protection BranchProtectionRule) []interface{} {
+	checks := make([]interface{}, 0, len(protection.RequiredStatusChecks))
+	for _, c := range protection.RequiredStatusChecks {
+		appID := ""
+		if c.App.ID != nil {
+			appID = fmt.Sprintf("%s", c.App.ID)
+		}
+		checks = append(checks, map[string]interface{}{
+			PROTECTION_STATUS_CHECK_APP_ID:  appID,
+			PROTECTION_STATUS_CHECK_CONTEXT: string(c.Context),
+		})
+	}
+	return checks
+}
+
+func setPushes(protection BranchProtectionRule) []interface{} {
 	if protection.RestrictsPushes == false {
 		return nil
 	}
 
-	pushAllowances := protection.PushAllowances.Nodes
-	pushActors := make([]string, 0, len(pushAllowances))
-	for _, p := range pushAllowances {
-		if p.Actor.Team != (Actor{}) {
-			pushActors = append(pushActors, p.Actor.Team.ID.(string))
+	return flattenAllowances(protection.PushAllowances.Nodes)
+}
+
+func flattenAllowances(allowances []Allowance) []interface{} {
+	actors := make([]interface{}, 0, len(allowances))
+	for _, a := range allowances {
+		if a.Actor.Typename == "" {
+			continue
+		}
+		actors = append(actors, flattenActor(a.Actor))
+	}
+
+	return actors
+}
+
+func flattenActor(actor Actor) map[string]interface{} {
+	m := map[string]interface{}{
+		ACTOR_APP:  "",
+		ACTOR_ID:   "",
+		ACTOR_TEAM: "",
+		ACTOR_TYPE: string(actor.Typename),
+		ACTOR_USER: "",
+	}
+
+	switch actor.Typename {
+	case "App":
+		m[ACTOR_APP] = string(actor.App.Slug)
+		m[ACTOR_ID] = fmt.Sprintf("%s", actor.App.ID)
+	case "Team":
+		m[ACTOR_TEAM] = string(actor.Team.Slug)
+		m[ACTOR_ID] = fmt.Sprintf("%s", actor.Team.ID)
+	case "User":
+		m[ACTOR_USER] = string(actor.User.Login)
+		m[ACTOR_ID] = fmt.Sprintf("%s", actor.User.ID)
+	}
+
+	return m
+}
+
+func expandActor(m map[string]interface{}) (ActorResourceData, error) {
+	data := ActorResourceData{}
+	count := 0
+
+	if v, ok := m[ACTOR_APP]; ok && v.(string) != "" {
+		data.App = v.(string)
+		count++
+	}
+	if v, ok := m[ACTOR_TEAM]; ok && v.(string) != "" {
+		data.Team = v.(string)
+		count++
+	}
+	if v, ok := m[ACTOR_USER]; ok && v.(string) != "" {
+		data.User = v.(string)
+		count++
+	}
+	if count != 1 {
+		return ActorResourceData{},
+			fmt.Errorf("error exactly one of %s, %s or %s must be declared per actor", ACTOR_APP, ACTOR_TEAM, ACTOR_USER)
+	}
+
+	return data, nil
+}
+
+// resolveActorIDs turns actor blocks into the node IDs expected by the
+// branch protection rule mutations.
+func resolveActorIDs(vL []interface{}, meta interface{}) ([]string, error) {
+	ids := make([]string, 0, len(vL))
+	for _, v := range vL {
+		actor, err := expandActor(v.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+
+		var id githubv4.ID
+		switch {
+		case actor.App != "":
+			id, err = getAppID(actor.App, meta)
+		case actor.Team != "":
+			id, err = getTeamID(actor.Team, meta)
+		case actor.User != "":
+			id, err = getUserID(actor.User, meta)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, fmt.Sprintf("%s", id))
+	}
+
+	return ids, nil
+}
+
+func getActors(ids []string, meta interface{}) ([]interface{}, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var query struct {
+		Nodes []Actor `graphql:"nodes(ids: $ids)"`
+	}
+	variables := map[string]interface{}{
+		"ids": githubv4IDSlice(ids),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	actors := make([]interface{}, 0, len(query.Nodes))
+	for _, a := range query.Nodes {
+		if a.Typename == "" {
+			continue
 		}
-		if p.Actor.User != (Actor{}) {
-			pushActors = append(pushActors, p.Actor.Team.ID.(string))
+		actors = append(actors, flattenActor(a))
+	}
+
+	return actors, nil
+}
+
+// getBranchProtectionRule reads a rule by node ID, paging through each of its
+// actor allowance connections until all of them are exhausted.
+func getBranchProtectionRule(ctx context.Context, id string, meta interface{}) (BranchProtectionRule, error) {
+	var query struct {
+		Node struct {
+			Node BranchProtectionRule `graphql:"... on BranchProtectionRule"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":                      githubv4.ID(id),
+		"bypassForcePushFirst":    githubv4.Int(100),
+		"bypassForcePushCursor":   (*githubv4.String)(nil),
+		"bypassPullRequestFirst":  githubv4.Int(100),
+		"bypassPullRequestCursor": (*githubv4.String)(nil),
+		"pushFirst":               githubv4.Int(100),
+		"pushCursor":              (*githubv4.String)(nil),
+		"dismissalFirst":          githubv4.Int(100),
+		"dismissalCursor":         (*githubv4.String)(nil),
+	}
+
+	client := meta.(*Organization).Client
+
+	var bypassForcePushAllowances []Allowance
+	var bypassPullRequestAllowances []Allowance
+	var pushAllowances []Allowance
+	var dismissalAllowances []Allowance
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return BranchProtectionRule{}, err
+		}
+
+		protection := query.Node.Node
+		bypassForcePushAllowances = append(bypassForcePushAllowances, protection.BypassForcePushAllowances.Nodes...)
+		bypassPullRequestAllowances = append(bypassPullRequestAllowances, protection.BypassPullRequestAllowances.Nodes...)
+		pushAllowances = append(pushAllowances, protection.PushAllowances.Nodes...)
+		dismissalAllowances = append(dismissalAllowances, protection.ReviewDismissalAllowances.Nodes...)
+
+		if !protection.BypassForcePushAllowances.PageInfo.HasNextPage &&
+			!protection.BypassPullRequestAllowances.PageInfo.HasNextPage &&
+			!protection.PushAllowances.PageInfo.HasNextPage &&
+			!protection.ReviewDismissalAllowances.PageInfo.HasNextPage {
+			break
 		}
+
+		// A connection that is already exhausted is queried past its last
+		// cursor so it returns no further nodes.
+		advanceCursor(variables, "bypassForcePushCursor", protection.BypassForcePushAllowances.PageInfo)
+		advanceCursor(variables, "bypassPullRequestCursor", protection.BypassPullRequestAllowances.PageInfo)
+		advanceCursor(variables, "pushCursor", protection.PushAllowances.PageInfo)
+		advanceCursor(variables, "dismissalCursor", protection.ReviewDismissalAllowances.PageInfo)
 	}
 
-	return pushActors
+	protection := query.Node.Node
+	protection.BypassForcePushAllowances.Nodes = bypassForcePushAllowances
+	protection.BypassPullRequestAllowances.Nodes = bypassPullRequestAllowances
+	protection.PushAllowances.Nodes = pushAllowances
+	protection.ReviewDismissalAllowances.Nodes = dismissalAllowances
+
+	return protection, nil
+}
+
+func advanceCursor(variables map[string]interface{}, key string, pageInfo PageInfo) {
+	if pageInfo.EndCursor != "" {
+		variables[key] = githubv4.NewString(pageInfo.EndCursor)
+	}
 }
 
-func getBranchProtectionID(name string, pattern string, meta interface{}) (githubv4.ID, error) {
+func getBranchProtectionID(owner string, name string, pattern string, meta interface{}) (githubv4.ID, error) {
 	var query struct {
 		Node struct {
 			Repository struct {
@@ -256,13 +653,13 @@ func getBranchProtectionID(name string, pattern string, meta interface{}) (githu
 		} `graphql:"repository(owner: $owner, name: $name)"`
 	}
 	variables := map[string]interface{}{
-		"owner":  githubv4.String(meta.(*Organization).Name),
+		"owner":  githubv4.String(owner),
 		"name":   githubv4.String(name),
 		"first":  githubv4.Int(100),
 		"cursor": (*githubv4.String)(nil),
 	}
 
-	ctx := context.Background()
+	ctx := meta.(*Organization).StopContext
 	client := meta.(*Organization).Client
 
 	var allRules []struct {
@@ -293,3 +690,89 @@ func getBranchProtectionID(name string, pattern string, meta interface{}) (githu
 
 	return id, nil
 }
+
+// getBranchProtectionRuleIDs lists the node ID of every branch protection
+// rule in a repository.
+func getBranchProtectionRuleIDs(ctx context.Context, repositoryID string, meta interface{}) ([]string, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				BranchProtectionRules struct {
+					Nodes []struct {
+						ID string
+					}
+					PageInfo PageInfo
+				} `graphql:"branchProtectionRules(first: $first, after: $cursor)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":     githubv4.ID(repositoryID),
+		"first":  githubv4.Int(100),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	client := meta.(*Organization).Client
+
+	var ids []string
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range query.Node.Repository.BranchProtectionRules.Nodes {
+			ids = append(ids, r.ID)
+		}
+
+		if !query.Node.Repository.BranchProtectionRules.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(query.Node.Repository.BranchProtectionRules.PageInfo.EndCursor)
+	}
+
+	return ids, nil
+}
+
+// getBranchProtectionMatchingRefs returns the names of the branches a rule's
+// pattern currently applies to.
+func getBranchProtectionMatchingRefs(ctx context.Context, id string, meta interface{}) ([]string, error) {
+	var query struct {
+		Node struct {
+			BranchProtectionRule struct {
+				MatchingRefs struct {
+					Nodes []struct {
+						Name githubv4.String
+					}
+					PageInfo PageInfo
+				} `graphql:"matchingRefs(first: $first, after: $cursor)"`
+			} `graphql:"... on BranchProtectionRule"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":     githubv4.ID(id),
+		"first":  githubv4.Int(100),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	client := meta.(*Organization).Client
+
+	refs := make([]string, 0)
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range query.Node.BranchProtectionRule.MatchingRefs.Nodes {
+			refs = append(refs, string(r.Name))
+		}
+
+		if !query.Node.BranchProtectionRule.MatchingRefs.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(query.Node.BranchProtectionRule.MatchingRefs.PageInfo.EndCursor)
+	}
+
+	return refs, nil
+}
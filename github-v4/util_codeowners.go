@@ -0,0 +1,126 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	CODEOWNERS_EXISTS          = "exists"
+	CODEOWNERS_INVALID_OWNERS  = "invalid_owners"
+	CODEOWNERS_LINE            = "line"
+	CODEOWNERS_OWNER           = "owner"
+	CODEOWNERS_OWNERS          = "owners"
+	CODEOWNERS_PATH            = "path"
+	CODEOWNERS_PATTERN         = "pattern"
+	CODEOWNERS_REASON          = "reason"
+	CODEOWNERS_REF             = "ref"
+	CODEOWNERS_RULE            = "rule"
+	CODEOWNERS_RULES           = "rules"
+	CODEOWNERS_VALIDATE_OWNERS = "validate_owners"
+)
+
+// CODEOWNERS_PATHS lists the locations GitHub reads a CODEOWNERS file from,
+// in order of precedence; the first one found is used.
+var CODEOWNERS_PATHS = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+type CodeownersRule struct {
+	Line    int
+	Owners  []string
+	Pattern string
+}
+
+// parseCodeowners returns the rules of a CODEOWNERS file in file order,
+// which matters as the last matching pattern takes precedence.
+func parseCodeowners(text string) []CodeownersRule {
+	var rules []CodeownersRule
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if j := strings.Index(line, " #"); j >= 0 {
+			line = line[:j]
+		}
+
+		fields := strings.Fields(line)
+		rules = append(rules, CodeownersRule{
+			Line:    i + 1,
+			Owners:  fields[1:],
+			Pattern: fields[0],
+		})
+	}
+	return rules
+}
+
+// renderCodeowners returns the canonical CODEOWNERS file for rules.
+func renderCodeowners(rules []CodeownersRule) string {
+	var b strings.Builder
+	b.WriteString("# This file is managed by Terraform; manual changes will be overwritten.\n\n")
+	for _, r := range rules {
+		b.WriteString(strings.Join(append([]string{r.Pattern}, r.Owners...), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// validateCodeowner reports why an owner would be ignored by GitHub, or ""
+// if it is valid. Owners must be users or teams with write access to the
+// repository; email addresses cannot be resolved and are assumed valid.
+func validateCodeowner(ctx context.Context, nameWithOwner string, owner string, meta interface{}) (string, error) {
+	if !strings.HasPrefix(owner, "@") {
+		if strings.Contains(owner, "@") {
+			return "", nil
+		}
+		return "not a user, team or email address", nil
+	}
+	owner = strings.TrimPrefix(owner, "@")
+
+	if i := strings.Index(owner, "/"); i >= 0 {
+		org, slug := owner[:i], owner[i+1:]
+		if !strings.EqualFold(org, strings.Split(nameWithOwner, "/")[0]) {
+			return "team does not belong to the repository owner", nil
+		}
+
+		var repository struct {
+			Permissions struct {
+				Push bool `json:"push"`
+			} `json:"permissions"`
+		}
+		path := fmt.Sprintf("/orgs/%s/teams/%s/repos/%s", org, slug, nameWithOwner)
+		status, err := restRequestMediaType(ctx, meta, "application/vnd.github.v3.repository+json", "GET", path, nil, &repository)
+		if status == http.StatusNotFound {
+			return "team does not exist or has no access", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !repository.Permissions.Push {
+			return "team does not have write access", nil
+		}
+		return "", nil
+	}
+
+	var collaborator struct {
+		Permission string `json:"permission"`
+	}
+	path := fmt.Sprintf("/repos/%s/collaborators/%s/permission", nameWithOwner, owner)
+	status, err := restRequest(ctx, meta, "GET", path, nil, &collaborator)
+	if status == http.StatusNotFound {
+		return "user does not exist", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if collaborator.Permission != "admin" && collaborator.Permission != "write" {
+		return "user does not have write access", nil
+	}
+
+	return "", nil
+}
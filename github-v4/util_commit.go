@@ -0,0 +1,256 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"strings"
+)
+
+const (
+	COMMIT_AUTHOR            = "commit_author"
+	COMMIT_BRANCH            = "branch"
+	COMMIT_EMAIL             = "commit_email"
+	COMMIT_MESSAGE           = "commit_message"
+	COMMIT_SHA               = "commit_sha"
+	FILE_CONTENT             = "content"
+	FILE_OVERWRITE_ON_CREATE = "overwrite_on_create"
+	FILE_PATH                = "file"
+	FILE_SHA                 = "sha"
+	PULL_REQUEST             = "pull_request"
+	PULL_REQUEST_BRANCH      = "pull_request_branch"
+	PULL_REQUEST_NUMBER      = "pull_request_number"
+	PULL_REQUEST_URL         = "pull_request_url"
+)
+
+// The vendored githubv4 predates the createCommitOnBranch mutation, so its
+// input types are declared here.
+type CreateCommitOnBranchInput struct {
+	Branch          CommittableBranch    `json:"branch"`
+	Message         CommitMessage        `json:"message"`
+	FileChanges     *FileChanges         `json:"fileChanges,omitempty"`
+	ExpectedHeadOid githubv4.GitObjectID `json:"expectedHeadOid"`
+}
+
+type CommittableBranch struct {
+	RepositoryNameWithOwner githubv4.String `json:"repositoryNameWithOwner"`
+	BranchName              githubv4.String `json:"branchName"`
+}
+
+type CommitMessage struct {
+	Headline githubv4.String  `json:"headline"`
+	Body     *githubv4.String `json:"body,omitempty"`
+}
+
+type FileChanges struct {
+	Additions []FileAddition `json:"additions,omitempty"`
+	Deletions []FileDeletion `json:"deletions,omitempty"`
+}
+
+type FileAddition struct {
+	Path     githubv4.String `json:"path"`
+	Contents githubv4.String `json:"contents"`
+}
+
+type FileDeletion struct {
+	Path githubv4.String `json:"path"`
+}
+
+// renderCommitMessage expands the {action}, {branch}, {path} and
+// {repository} placeholders in a commit message template.
+func renderCommitMessage(template string, action string, nameWithOwner string, branch string, path string) string {
+	return strings.NewReplacer(
+		"{action}", action,
+		"{branch}", branch,
+		"{path}", path,
+		"{repository}", nameWithOwner,
+	).Replace(template)
+}
+
+// commitAuthorTrailer credits an author in the commit body. Commits made
+// through createCommitOnBranch are always authored by the token's owner, so
+// a Co-authored-by trailer is the only way to attribute them to someone else.
+func commitAuthorTrailer(name string, email string) string {
+	if name == "" || email == "" {
+		return ""
+	}
+	return fmt.Sprintf("Co-authored-by: %s <%s>", name, email)
+}
+
+// getFileContent returns the text of the file at path on ref along with its
+// blob oid, which is empty if there is no such file. Binary files are
+// rejected, as GraphQL only returns the text of a blob.
+func getFileContent(ctx context.Context, repositoryID string, ref string, path string, meta interface{}) (string, string, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				Object struct {
+					Blob struct {
+						IsBinary githubv4.Boolean
+						Oid      githubv4.GitObjectID
+						Text     githubv4.String
+					} `graphql:"... on Blob"`
+				} `graphql:"object(expression: $expression)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":         githubv4.ID(repositoryID),
+		"expression": githubv4.String(fmt.Sprintf("%s:%s", ref, path)),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", "", err
+	}
+
+	blob := query.Node.Repository.Object.Blob
+	if blob.IsBinary {
+		return "", "", fmt.Errorf("error %s on %s is a binary file; only text files are supported", path, ref)
+	}
+
+	return string(blob.Text), string(blob.Oid), nil
+}
+
+// commitOnBranch commits the given file additions and deletions on top of
+// expectedHeadOid, failing rather than overwriting if the branch has moved
+// on. The new commit's oid is returned.
+func commitOnBranch(ctx context.Context, nameWithOwner string, branch string, expectedHeadOid githubv4.GitObjectID, headline string, body string, additions map[string]string, deletions []string, meta interface{}) (string, error) {
+	var mutate struct {
+		CreateCommitOnBranch struct {
+			Commit struct {
+				Oid githubv4.GitObjectID
+			}
+		} `graphql:"createCommitOnBranch(input: $input)"`
+	}
+
+	changes := FileChanges{}
+	for path, contents := range additions {
+		changes.Additions = append(changes.Additions, FileAddition{
+			Path:     githubv4.String(path),
+			Contents: githubv4.String(base64.StdEncoding.EncodeToString([]byte(contents))),
+		})
+	}
+	for _, path := range deletions {
+		changes.Deletions = append(changes.Deletions, FileDeletion{
+			Path: githubv4.String(path),
+		})
+	}
+
+	input := CreateCommitOnBranchInput{
+		Branch: CommittableBranch{
+			RepositoryNameWithOwner: githubv4.String(nameWithOwner),
+			BranchName:              githubv4.String(branch),
+		},
+		Message: CommitMessage{
+			Headline: githubv4.String(headline),
+		},
+		FileChanges:     &changes,
+		ExpectedHeadOid: expectedHeadOid,
+	}
+	if body != "" {
+		input.Message.Body = githubv4.NewString(githubv4.String(body))
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Mutate(ctx, &mutate, input, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(mutate.CreateCommitOnBranch.Commit.Oid), nil
+}
+
+type PullRequest struct {
+	ID          githubv4.ID
+	HeadRefName githubv4.String
+	Number      githubv4.Int
+	State       githubv4.PullRequestState
+	URL         githubv4.URI
+}
+
+// ensurePullRequest returns the open pull request from head into base,
+// opening one if there is none.
+func ensurePullRequest(ctx context.Context, repositoryID string, base string, head string, title string, meta interface{}) (PullRequest, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				PullRequests struct {
+					Nodes []PullRequest
+				} `graphql:"pullRequests(first: 1, baseRefName: $base, headRefName: $head, states: OPEN)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":   githubv4.ID(repositoryID),
+		"base": githubv4.String(base),
+		"head": githubv4.String(head),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	if len(query.Node.Repository.PullRequests.Nodes) > 0 {
+		return query.Node.Repository.PullRequests.Nodes[0], nil
+	}
+
+	var mutate struct {
+		CreatePullRequest struct {
+			PullRequest PullRequest
+		} `graphql:"createPullRequest(input: $input)"`
+	}
+	input := githubv4.CreatePullRequestInput{
+		RepositoryID: githubv4.ID(repositoryID),
+		BaseRefName:  githubv4.String(base),
+		HeadRefName:  githubv4.String(head),
+		Title:        githubv4.String(title),
+	}
+	err = client.Mutate(ctx, &mutate, input, nil)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	return mutate.CreatePullRequest.PullRequest, nil
+}
+
+func getPullRequest(ctx context.Context, repositoryID string, number int, meta interface{}) (PullRequest, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				PullRequest PullRequest `graphql:"pullRequest(number: $number)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":     githubv4.ID(repositoryID),
+		"number": githubv4.Int(number),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	return query.Node.Repository.PullRequest, nil
+}
+
+func closePullRequest(ctx context.Context, id githubv4.ID, meta interface{}) error {
+	var mutate struct {
+		ClosePullRequest struct {
+			PullRequest struct {
+				ID githubv4.ID
+			}
+		} `graphql:"closePullRequest(input: $input)"`
+	}
+	input := githubv4.ClosePullRequestInput{
+		PullRequestID: id,
+	}
+
+	client := meta.(*Organization).Client
+	return client.Mutate(ctx, &mutate, input, nil)
+}
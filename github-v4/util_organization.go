@@ -1,6 +1,106 @@
 package github
 
+import (
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"strings"
+)
+
 const (
-	ORGANIZATION_MEMBERS      = "members"
-	ORGANIZATION_REPOSITORIES = "repositories"
+	INVITATION_CREATED_AT           = "created_at"
+	INVITATION_EMAIL                = "email"
+	INVITATION_FAILED_AT            = "failed_at"
+	INVITATION_FAILED_REASON        = "failed_reason"
+	INVITATION_ID                   = "invitation_id"
+	INVITATION_INVITER              = "inviter"
+	MEMBERSHIP_STATE                = "state"
+	ORGANIZATION_FAILED_INVITATIONS = "failed_invitations"
+	ORGANIZATION_INVITATIONS        = "invitations"
+	ORGANIZATION_MEMBERS            = "members"
+	ORGANIZATION_REPOSITORIES       = "repositories"
+
+	// The states reported by the REST membership endpoints.
+	MEMBERSHIP_STATE_ACTIVE  = "active"
+	MEMBERSHIP_STATE_PENDING = "pending"
 )
+
+// OrganizationInvitation is an invitation as returned by the REST API; the
+// GraphQL API only exposes the invitee of pending invitations.
+type OrganizationInvitation struct {
+	ID           int    `json:"id"`
+	Login        string `json:"login"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	CreatedAt    string `json:"created_at"`
+	FailedAt     string `json:"failed_at"`
+	FailedReason string `json:"failed_reason"`
+	Inviter      struct {
+		Login string `json:"login"`
+	} `json:"inviter"`
+}
+
+func getOrganizationID(meta interface{}) (githubv4.ID, error) {
+	var query struct {
+		Organization struct {
+			ID githubv4.ID
+		} `graphql:"organization(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login": githubv4.String(meta.(*Organization).Name),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.Organization.ID, nil
+}
+
+// getOrganizationInvitations pages through the organization's pending
+// invitations, or those which expired or otherwise failed.
+func getOrganizationInvitations(ctx context.Context, failed bool, meta interface{}) ([]OrganizationInvitation, error) {
+	endpoint := "invitations"
+	if failed {
+		endpoint = "failed_invitations"
+	}
+
+	var allInvitations []OrganizationInvitation
+	for page := 1; ; page++ {
+		var invitations []OrganizationInvitation
+		path := fmt.Sprintf("/orgs/%s/%s?per_page=100&page=%d", meta.(*Organization).Name, endpoint, page)
+		_, err := restRequest(ctx, meta, "GET", path, nil, &invitations)
+		if err != nil {
+			return nil, err
+		}
+
+		allInvitations = append(allInvitations, invitations...)
+
+		if len(invitations) < 100 {
+			break
+		}
+	}
+
+	return allInvitations, nil
+}
+
+// organizationRestRole maps the GraphQL OrganizationMemberRole enum to the
+// role expected by the REST membership and invitation endpoints.
+func organizationRestRole(role string, invitation bool) string {
+	if role == string(githubv4.OrganizationMemberRoleMember) && invitation {
+		return "direct_member"
+	}
+	return strings.ToLower(role)
+}
+
+// organizationRole maps a REST membership or invitation role back to the
+// GraphQL enum.
+func organizationRole(role string) string {
+	if role == "direct_member" {
+		return string(githubv4.OrganizationMemberRoleMember)
+	}
+	return strings.ToUpper(role)
+}
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,6 +26,12 @@ const (
 	PROVIDER_APP_PEM             = "pem"
 	PROVIDER_APP_ID              = "id"
 	PROVIDER_APP_INSTALLATION_ID = "inst"
+	PROVIDER_MAX_RETRIES         = "max_retries"
+	PROVIDER_POINT_BUDGET        = "point_budget"
+
+	// Installation tokens are valid for one hour; refresh a little early so
+	// requests in flight do not race the expiry.
+	APP_TOKEN_REFRESH_WINDOW = 5 * time.Minute
 )
 
 type Config struct {
@@ -33,37 +41,55 @@ type Config struct {
 	Pem            string
 	AppID          string
 	InstallationID string
+	MaxRetries     int
+	PointBudget    int
 }
 
 type Organization struct {
 	Name        string
-	Token       string
+	TokenSource oauth2.TokenSource
 	Client      *githubv4.Client
+	HTTPClient  *http.Client
+	RestURL     string
 	StopContext context.Context
 }
 
 type TokenResponse struct {
-	Token string `json:"token"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 func (c *Config) Clients() (interface{}, error) {
 	var org Organization
 
-	token := c.Token
-	if token == "" && c.InstallationID != "" {
-		t, err := newAppToken(c)
-		if err != nil {
+	var transport http.RoundTripper
+	if c.Token == "" && c.InstallationID != "" {
+		source := &appTokenSource{config: c}
+		if _, err := source.Token(); err != nil {
 			return nil, fmt.Errorf("error returning GitHub App installation token: %w", err)
 		}
-		token = t
+		org.TokenSource = source
+		transport = &appTokenTransport{
+			Source: source,
+			Base:   http.DefaultTransport,
+		}
+	} else {
+		org.TokenSource = oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: c.Token},
+		)
+		transport = &oauth2.Transport{
+			Source: org.TokenSource,
+			Base:   http.DefaultTransport,
+		}
 	}
 
-	httpClient := oauth2.NewClient(
-		context.Background(),
-		oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: token},
-		),
-	)
+	httpClient := &http.Client{
+		Transport: &rateLimitTransport{
+			Base:        transport,
+			MaxRetries:  c.MaxRetries,
+			PointBudget: c.PointBudget,
+		},
+	}
 
 	uGQL, err := url.Parse(c.BaseURL)
 	if err != nil {
@@ -72,17 +98,107 @@ func (c *Config) Clients() (interface{}, error) {
 	uGQL.Path = path.Join(uGQL.Path, "graphql")
 	graphQLClient := githubv4.NewEnterpriseClient(uGQL.String(), httpClient)
 
+	restURL, err := restBaseURL(c.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	org.Client = graphQLClient
+	org.HTTPClient = httpClient
+	org.RestURL = restURL
 	org.Name = c.Organization
-	org.Token = token
 	return &org, nil
 }
 
-func newAppToken(c *Config) (string, error) {
-	c.Pem = strings.ReplaceAll(c.Pem, "\\n", "\n")
-	rsaPrivate, err := crypto.ParseRSAPrivateKeyFromPEM([]byte(c.Pem))
+// appTokenSource mints GitHub App installation tokens on demand, re-minting
+// once the current token comes within APP_TOKEN_REFRESH_WINDOW of expiry.
+type appTokenSource struct {
+	config *Config
+	mu     sync.Mutex
+	token  *oauth2.Token
+}
+
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Now().Add(APP_TOKEN_REFRESH_WINDOW).Before(s.token.Expiry) {
+		return s.token, nil
+	}
+
+	t, err := newAppToken(s.config)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	s.token = t
+
+	return s.token, nil
+}
+
+// invalidate discards the current token if it is still the one given,
+// forcing the next call to Token to mint a new one.
+func (s *appTokenSource) invalidate(t *oauth2.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == t {
+		s.token = nil
+	}
+}
+
+// appTokenTransport authorises requests with the current installation token
+// and retries once with a fresh token when GitHub responds 401.
+type appTokenTransport struct {
+	Source *appTokenSource
+	Base   http.RoundTripper
+}
+
+func (t *appTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.Base.RoundTrip(authorizedRequest(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	// The request can only be replayed if its body can be rewound.
+	if req.Body != nil && req.GetBody == nil {
+		return res, nil
+	}
+
+	t.Source.invalidate(token)
+	token, err = t.Source.Token()
+	if err != nil {
+		return res, nil
+	}
+
+	retry := authorizedRequest(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return res, nil
+		}
+		retry.Body = body
+	}
+	res.Body.Close()
+
+	return t.Base.RoundTrip(retry)
+}
+
+func authorizedRequest(req *http.Request, token *oauth2.Token) *http.Request {
+	r := req.Clone(req.Context())
+	token.SetAuthHeader(r)
+	return r
+}
+
+func newAppToken(c *Config) (*oauth2.Token, error) {
+	pem := strings.ReplaceAll(c.Pem, "\\n", "\n")
+	rsaPrivate, err := crypto.ParseRSAPrivateKeyFromPEM([]byte(pem))
+	if err != nil {
+		return nil, err
 	}
 
 	claims := jws.Claims{}
@@ -93,21 +209,17 @@ func newAppToken(c *Config) (string, error) {
 
 	bearer, err := jwt.Serialize(rsaPrivate)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	u, err := url.Parse(c.BaseURL)
+	baseURL, err := restBaseURL(c.BaseURL)
 	if err != nil {
-		return "", err
-	}
-	baseURL := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
-	if u.String() != "https://api.github.com/" {
-		baseURL = fmt.Sprintf("%s://%s/api/v3", u.Scheme, u.Host)
+		return nil, err
 	}
 	tokenURL := fmt.Sprintf("%s/app/installations/%s/access_tokens", baseURL, c.InstallationID)
 	req, err := http.NewRequest("POST", tokenURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", bearer))
 	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
@@ -117,22 +229,91 @@ func newAppToken(c *Config) (string, error) {
 		defer res.Body.Close()
 	}
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if res.StatusCode != 201 {
-		return "", fmt.Errorf("status code returned (%d) is not 201", res.StatusCode)
+		return nil, fmt.Errorf("status code returned (%d) is not 201", res.StatusCode)
 	}
 
 	bodyBytes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	tokenRes := TokenResponse{}
 	err = json.Unmarshal([]byte(string(bodyBytes)), &tokenRes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: tokenRes.Token,
+		Expiry:      tokenRes.ExpiresAt,
+	}, nil
+}
+
+// restBaseURL returns the REST (v3) API root for the configured base URL,
+// for the few operations GraphQL does not cover.
+func restBaseURL(base string) (string, error) {
+	u, err := url.Parse(base)
 	if err != nil {
 		return "", err
 	}
+	baseURL := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	if u.String() != "https://api.github.com/" {
+		baseURL = fmt.Sprintf("%s://%s/api/v3", u.Scheme, u.Host)
+	}
+	return baseURL, nil
+}
+
+// restRequest performs a REST (v3) call through the provider's HTTP client,
+// decoding a successful response into out when it is given. The status code
+// is returned so callers can treat 404s as they see fit.
+func restRequest(ctx context.Context, meta interface{}, method string, path string, body interface{}, out interface{}) (int, error) {
+	return restRequestMediaType(ctx, meta, "application/vnd.github.v3+json", method, path, body, out)
+}
+
+// restRequestMediaType is restRequest for endpoints whose response depends on
+// the requested media type.
+func restRequestMediaType(ctx context.Context, meta interface{}, mediaType string, method string, path string, body interface{}, out interface{}) (int, error) {
+	org := meta.(*Organization)
+
+	var buf bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&buf).Encode(body)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", org.RestURL, path), &buf)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", mediaType)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := org.HTTPClient.Do(req)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return 0, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		bodyBytes, _ := ioutil.ReadAll(res.Body)
+		return res.StatusCode, fmt.Errorf("%s %s returned status code (%d): %s", method, path, res.StatusCode, bodyBytes)
+	}
+
+	if out != nil {
+		err = json.NewDecoder(res.Body).Decode(out)
+		if err != nil {
+			return res.StatusCode, err
+		}
+	}
 
-	return tokenRes.Token, nil
+	return res.StatusCode, nil
 }
@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	REF_BRANCH        = "branch"
+	REF_FORCE         = "force"
+	REF_NAME          = "ref"
+	REF_SHA           = "sha"
+	REF_SOURCE_BRANCH = "source_branch"
+	REF_SOURCE_SHA    = "source_sha"
+	REF_SOURCE_TAG    = "source_tag"
+	REF_TAG           = "tag"
+)
+
+const (
+	REF_NAMESPACE_BRANCH = "refs/heads/"
+	REF_NAMESPACE_TAG    = "refs/tags/"
+)
+
+type Ref struct {
+	ID     githubv4.ID
+	Name   githubv4.String
+	Prefix githubv4.String
+	Target struct {
+		Oid githubv4.GitObjectID
+	}
+}
+
+// getRef returns the ref with the fully qualified name, with an empty ID if
+// there is no such ref.
+func getRef(ctx context.Context, repositoryID string, qualifiedName string, meta interface{}) (Ref, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				Ref Ref `graphql:"ref(qualifiedName: $qualifiedName)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":            githubv4.ID(repositoryID),
+		"qualifiedName": githubv4.String(qualifiedName),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return Ref{}, err
+	}
+
+	return query.Node.Repository.Ref, nil
+}
+
+// getBranchRef returns the ref of a branch, with an empty ID if the branch
+// does not exist.
+func getBranchRef(ctx context.Context, repositoryID string, branch string, meta interface{}) (Ref, error) {
+	return getRef(ctx, repositoryID, REF_NAMESPACE_BRANCH+branch, meta)
+}
+
+// resolveCommit returns the oid of the commit a rev-parse style expression
+// names, peeling annotated tags. It fails if nothing matches.
+func resolveCommit(ctx context.Context, repositoryID string, expression string, meta interface{}) (githubv4.GitObjectID, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				Object struct {
+					Typename githubv4.String `graphql:"__typename"`
+					Oid      githubv4.GitObjectID
+					Tag      struct {
+						Target struct {
+							Oid githubv4.GitObjectID
+						}
+					} `graphql:"... on Tag"`
+				} `graphql:"object(expression: $expression)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":         githubv4.ID(repositoryID),
+		"expression": githubv4.String(expression),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", err
+	}
+
+	object := query.Node.Repository.Object
+	switch object.Typename {
+	case "Commit":
+		return object.Oid, nil
+	case "Tag":
+		return object.Tag.Target.Oid, nil
+	case "":
+		return "", fmt.Errorf("error %s does not exist in repository %s", expression, repositoryID)
+	default:
+		return "", fmt.Errorf("error %s is a %s rather than a commit", expression, object.Typename)
+	}
+}
+
+func createRef(ctx context.Context, repositoryID string, qualifiedName string, oid githubv4.GitObjectID, meta interface{}) (Ref, error) {
+	var mutate struct {
+		CreateRef struct {
+			Ref Ref
+		} `graphql:"createRef(input: $input)"`
+	}
+	input := githubv4.CreateRefInput{
+		RepositoryID: githubv4.ID(repositoryID),
+		Name:         githubv4.String(qualifiedName),
+		Oid:          oid,
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Mutate(ctx, &mutate, input, nil)
+	if err != nil {
+		return Ref{}, err
+	}
+
+	return mutate.CreateRef.Ref, nil
+}
+
+// updateRef points a ref at oid. Without force the update must be a fast
+// forward.
+func updateRef(ctx context.Context, id githubv4.ID, oid githubv4.GitObjectID, force bool, meta interface{}) error {
+	var mutate struct {
+		UpdateRef struct {
+			Ref struct {
+				ID githubv4.ID
+			}
+		} `graphql:"updateRef(input: $input)"`
+	}
+	input := githubv4.UpdateRefInput{
+		RefID: id,
+		Oid:   oid,
+		Force: githubv4.NewBoolean(githubv4.Boolean(force)),
+	}
+
+	client := meta.(*Organization).Client
+	return client.Mutate(ctx, &mutate, input, nil)
+}
+
+func deleteRef(ctx context.Context, id githubv4.ID, meta interface{}) error {
+	var mutate struct {
+		DeleteRef struct { // Empty struct does not work
+			ClientMutationId githubv4.ID
+		} `graphql:"deleteRef(input: $input)"`
+	}
+	input := githubv4.DeleteRefInput{
+		RefID: id,
+	}
+
+	client := meta.(*Organization).Client
+	return client.Mutate(ctx, &mutate, input, nil)
+}
+
+// resetBranch points branch at oid, creating it if need be. Any commits
+// already on the branch are discarded.
+func resetBranch(ctx context.Context, repositoryID string, branch string, oid githubv4.GitObjectID, meta interface{}) error {
+	ref, err := getBranchRef(ctx, repositoryID, branch, meta)
+	if err != nil {
+		return err
+	}
+
+	if ref.ID == nil {
+		_, err = createRef(ctx, repositoryID, REF_NAMESPACE_BRANCH+branch, oid, meta)
+		return err
+	}
+
+	return updateRef(ctx, ref.ID, oid, true, meta)
+}
@@ -2,16 +2,224 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/shurcooL/githubv4"
+	"strings"
 )
 
 const (
-	CODEOWNERS_EXISTS        = "exists"
-	REPOSITORY_COLLABORATORS = "collaborators"
-	REPOSITORY_ID            = "repository_id"
-	REPOSITORY_NAME          = "name"
+	COLLABORATOR_AFFILIATION          = "affiliation"
+	PERMISSION_SOURCES                = "permission_sources"
+	PERMISSION_SOURCE_ID              = "source_id"
+	PERMISSION_SOURCE_NAME            = "source_name"
+	PERMISSION_SOURCE_TYPE            = "source_type"
+	REPOSITORIES_INCLUDE_ARCHIVED     = "include_archived"
+	REPOSITORIES_INCLUDE_FORKS        = "include_forks"
+	REPOSITORIES_LANGUAGE             = "language"
+	REPOSITORIES_NAME_REGEX           = "name_regex"
+	REPOSITORIES_TOPIC                = "topic"
+	REPOSITORY_ALLOW_AUTO_MERGE       = "allow_auto_merge"
+	REPOSITORY_ALLOW_MERGE_COMMIT     = "allow_merge_commit"
+	REPOSITORY_ALLOW_REBASE_MERGE     = "allow_rebase_merge"
+	REPOSITORY_ALLOW_SQUASH_MERGE     = "allow_squash_merge"
+	REPOSITORY_ARCHIVED               = "archived"
+	REPOSITORY_COLLABORATORS          = "collaborators"
+	REPOSITORY_CREATED_AT             = "created_at"
+	REPOSITORY_DEFAULT_BRANCH         = "default_branch"
+	REPOSITORY_DELETE_BRANCH_ON_MERGE = "delete_branch_on_merge"
+	REPOSITORY_DESCRIPTION            = "description"
+	REPOSITORY_DISK_USAGE             = "disk_usage"
+	REPOSITORY_FORK                   = "fork"
+	REPOSITORY_FULL_NAME              = "full_name"
+	REPOSITORY_HAS_DISCUSSIONS        = "has_discussions"
+	REPOSITORY_HAS_ISSUES             = "has_issues"
+	REPOSITORY_HAS_PROJECTS           = "has_projects"
+	REPOSITORY_HAS_WIKI               = "has_wiki"
+	REPOSITORY_HOMEPAGE_URL           = "homepage_url"
+	REPOSITORY_ID                     = "repository_id"
+	REPOSITORY_IS_TEMPLATE            = "is_template"
+	REPOSITORY_LANGUAGES              = "languages"
+	REPOSITORY_LANGUAGE_SIZE          = "size"
+	REPOSITORY_LICENSE                = "license"
+	REPOSITORY_MIRROR                 = "mirror"
+	REPOSITORY_NAME                   = "name"
+	REPOSITORY_PARENT                 = "parent"
+	REPOSITORY_PARENT_ID              = "parent_id"
+	REPOSITORY_PRIMARY_LANGUAGE       = "primary_language"
+	REPOSITORY_PUSHED_AT              = "pushed_at"
+	REPOSITORY_TEMPLATE               = "template"
+	REPOSITORY_TEMPLATE_ID            = "template_id"
+	REPOSITORY_TOPICS                 = "topics"
+	REPOSITORY_VISIBILITY             = "visibility"
+	SEARCH_ALLOW_TRUNCATED            = "allow_truncated"
+	SEARCH_QUERY                      = "query"
+	SEARCH_TOTAL_COUNT                = "total_count"
+	SEARCH_TRUNCATED                  = "truncated"
 )
 
+// GitHub search never returns more than this many results for a query.
+const SEARCH_RESULT_LIMIT = 1000
+
+type Repository struct {
+	DefaultBranchRef struct {
+		Name githubv4.String
+	}
+	AutoMergeAllowed      githubv4.Boolean
+	DeleteBranchOnMerge   githubv4.Boolean
+	Description           githubv4.String
+	HasDiscussionsEnabled githubv4.Boolean
+	HasIssuesEnabled      githubv4.Boolean
+	HasProjectsEnabled    githubv4.Boolean
+	HasWikiEnabled        githubv4.Boolean
+	HomepageURL           githubv4.String `graphql:"homepageUrl"`
+	ID                    githubv4.ID
+	IsArchived            githubv4.Boolean
+	IsTemplate            githubv4.Boolean
+	MergeCommitAllowed    githubv4.Boolean
+	Name                  githubv4.String
+	RebaseMergeAllowed    githubv4.Boolean
+	SquashMergeAllowed    githubv4.Boolean
+	Visibility            githubv4.RepositoryVisibility
+}
+
+// PermissionSource explains one way a collaborator came to have access,
+// distinguished by __typename as every fragment shares the `id` field.
+type PermissionSource struct {
+	Permission githubv4.DefaultRepositoryPermissionField
+	Source     struct {
+		Typename     githubv4.String `graphql:"__typename"`
+		Organization struct {
+			ID    githubv4.ID
+			Login githubv4.String
+		} `graphql:"... on Organization"`
+		Repository struct {
+			ID   githubv4.ID
+			Name githubv4.String
+		} `graphql:"... on Repository"`
+		Team struct {
+			ID   githubv4.ID
+			Slug githubv4.String
+		} `graphql:"... on Team"`
+	}
+}
+
+// RepositoryInvitation is a collaborator invitation as returned by the REST
+// API.
+type RepositoryInvitation struct {
+	ID          int    `json:"id"`
+	Permissions string `json:"permissions"`
+	Invitee     struct {
+		Login string `json:"login"`
+	} `json:"invitee"`
+}
+
+// RepositoryDetails extends Repository with the read-only properties exposed
+// by the data sources. The data sources fetch it 100 repositories at a time,
+// so its connections are kept small: only the largest languages are listed,
+// and GitHub allows no more than 20 topics on a repository.
+type RepositoryDetails struct {
+	Repository
+	Languages struct {
+		Edges []struct {
+			Node struct {
+				Name githubv4.String
+			}
+			Size githubv4.Int
+		}
+	} `graphql:"languages(first: 10, orderBy: {field: SIZE, direction: DESC})"`
+	LicenseInfo struct {
+		SpdxID githubv4.String `graphql:"spdxId"`
+	}
+	Parent struct {
+		ID            githubv4.ID
+		NameWithOwner githubv4.String
+	}
+	PrimaryLanguage struct {
+		Name githubv4.String
+	}
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name githubv4.String
+			}
+		}
+	} `graphql:"repositoryTopics(first: 20)"`
+	TemplateRepository struct {
+		ID            githubv4.ID
+		NameWithOwner githubv4.String
+	}
+	CreatedAt     githubv4.DateTime
+	DiskUsage     githubv4.Int
+	IsFork        githubv4.Boolean
+	IsMirror      githubv4.Boolean
+	NameWithOwner githubv4.String
+	PushedAt      githubv4.DateTime
+}
+
+type RepositoryResourceData struct {
+	AllowAutoMerge      bool
+	AllowMergeCommit    bool
+	AllowRebaseMerge    bool
+	AllowSquashMerge    bool
+	Archived            bool
+	DefaultBranch       string
+	DeleteBranchOnMerge bool
+	Description         string
+	HasDiscussions      bool
+	HasIssues           bool
+	HasProjects         bool
+	HasWiki             bool
+	HomepageURL         string
+	IsTemplate          bool
+	Name                string
+	Visibility          string
+}
+
+// The vendored githubv4 predates the discussions setting and the unarchive
+// mutation, so their inputs are declared here.
+type UpdateRepositoryInput struct {
+	RepositoryID          githubv4.ID       `json:"repositoryId"`
+	Description           *githubv4.String  `json:"description,omitempty"`
+	HasDiscussionsEnabled *githubv4.Boolean `json:"hasDiscussionsEnabled,omitempty"`
+	HasIssuesEnabled      *githubv4.Boolean `json:"hasIssuesEnabled,omitempty"`
+	HasProjectsEnabled    *githubv4.Boolean `json:"hasProjectsEnabled,omitempty"`
+	HasWikiEnabled        *githubv4.Boolean `json:"hasWikiEnabled,omitempty"`
+	HomepageURL           *githubv4.String  `json:"homepageUrl,omitempty"`
+	Name                  *githubv4.String  `json:"name,omitempty"`
+	Template              *githubv4.Boolean `json:"template,omitempty"`
+}
+
+type UnarchiveRepositoryInput struct {
+	RepositoryID githubv4.ID `json:"repositoryId"`
+}
+
+func repositoryResourceData(d *schema.ResourceData) RepositoryResourceData {
+	data := RepositoryResourceData{}
+
+	data.Name = d.Get(REPOSITORY_NAME).(string)
+	data.Description = d.Get(REPOSITORY_DESCRIPTION).(string)
+	data.HomepageURL = d.Get(REPOSITORY_HOMEPAGE_URL).(string)
+	data.Visibility = d.Get(REPOSITORY_VISIBILITY).(string)
+	data.IsTemplate = d.Get(REPOSITORY_IS_TEMPLATE).(bool)
+	data.Archived = d.Get(REPOSITORY_ARCHIVED).(bool)
+	data.HasDiscussions = d.Get(REPOSITORY_HAS_DISCUSSIONS).(bool)
+	data.HasIssues = d.Get(REPOSITORY_HAS_ISSUES).(bool)
+	data.HasProjects = d.Get(REPOSITORY_HAS_PROJECTS).(bool)
+	data.HasWiki = d.Get(REPOSITORY_HAS_WIKI).(bool)
+	data.AllowAutoMerge = d.Get(REPOSITORY_ALLOW_AUTO_MERGE).(bool)
+	data.AllowMergeCommit = d.Get(REPOSITORY_ALLOW_MERGE_COMMIT).(bool)
+	data.AllowRebaseMerge = d.Get(REPOSITORY_ALLOW_REBASE_MERGE).(bool)
+	data.AllowSquashMerge = d.Get(REPOSITORY_ALLOW_SQUASH_MERGE).(bool)
+	data.DeleteBranchOnMerge = d.Get(REPOSITORY_DELETE_BRANCH_ON_MERGE).(bool)
+
+	if v, ok := d.GetOk(REPOSITORY_DEFAULT_BRANCH); ok {
+		data.DefaultBranch = v.(string)
+	}
+
+	return data
+}
+
 func getRepositoryID(name string, meta interface{}) (githubv4.ID, error) {
 	var query struct {
 		Repository struct {
@@ -22,8 +230,8 @@ func getRepositoryID(name string, meta interface{}) (githubv4.ID, error) {
 		"owner": githubv4.String(meta.(*Organization).Name),
 		"name":  githubv4.String(name),
 	}
-	ctx := context.Background()
-	client := meta.(Organization).Client
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
 	err := client.Query(ctx, &query, variables)
 	if err != nil {
 		return nil, err
@@ -31,3 +239,225 @@ func getRepositoryID(name string, meta interface{}) (githubv4.ID, error) {
 
 	return query.Repository.ID, nil
 }
+
+// The REST API names the built-in repository roles differently from the
+// GraphQL RepositoryPermission enum; anything else is a custom role and is
+// passed through unchanged.
+var repositoryRestPermissions = map[string]string{
+	string(githubv4.RepositoryPermissionRead):     "pull",
+	string(githubv4.RepositoryPermissionTriage):   "triage",
+	string(githubv4.RepositoryPermissionWrite):    "push",
+	string(githubv4.RepositoryPermissionMaintain): "maintain",
+	string(githubv4.RepositoryPermissionAdmin):    "admin",
+}
+
+func repositoryRestPermission(permission string) string {
+	if v, ok := repositoryRestPermissions[permission]; ok {
+		return v
+	}
+	return permission
+}
+
+// repositoryRoleNamePermission maps the role_name reported by the REST API back to
+// the GraphQL enum, leaving custom role names as they are.
+func repositoryRoleNamePermission(roleName string) string {
+	switch roleName {
+	case "read", "pull":
+		return string(githubv4.RepositoryPermissionRead)
+	case "write", "push":
+		return string(githubv4.RepositoryPermissionWrite)
+	case "triage", "maintain", "admin":
+		return strings.ToUpper(roleName)
+	}
+	return roleName
+}
+
+// getRepositoryNameWithOwner resolves a repository node ID to the
+// `owner/name` form used by the REST API.
+func getRepositoryNameWithOwner(ctx context.Context, id string, meta interface{}) (string, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				NameWithOwner githubv4.String
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": githubv4.ID(id),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", err
+	}
+
+	return string(query.Node.Repository.NameWithOwner), nil
+}
+
+func flattenPermissionSources(sources []PermissionSource) []interface{} {
+	out := make([]interface{}, 0, len(sources))
+	for _, s := range sources {
+		m := map[string]interface{}{
+			USER_PERMISSION:        string(s.Permission),
+			PERMISSION_SOURCE_TYPE: string(s.Source.Typename),
+		}
+
+		switch s.Source.Typename {
+		case "Organization":
+			m[PERMISSION_SOURCE_ID] = fmt.Sprintf("%s", s.Source.Organization.ID)
+			m[PERMISSION_SOURCE_NAME] = string(s.Source.Organization.Login)
+		case "Repository":
+			m[PERMISSION_SOURCE_ID] = fmt.Sprintf("%s", s.Source.Repository.ID)
+			m[PERMISSION_SOURCE_NAME] = string(s.Source.Repository.Name)
+		case "Team":
+			m[PERMISSION_SOURCE_ID] = fmt.Sprintf("%s", s.Source.Team.ID)
+			m[PERMISSION_SOURCE_NAME] = string(s.Source.Team.Slug)
+		}
+
+		out = append(out, m)
+	}
+	return out
+}
+
+// repositoryDetailsSchema returns the computed attributes populated by
+// flattenRepositoryDetails.
+func repositoryDetailsSchema() map[string]*schema.Schema {
+	computed := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{
+			Type:     t,
+			Computed: true,
+		}
+	}
+
+	return map[string]*schema.Schema{
+		REPOSITORY_ALLOW_AUTO_MERGE:       computed(schema.TypeBool),
+		REPOSITORY_ALLOW_MERGE_COMMIT:     computed(schema.TypeBool),
+		REPOSITORY_ALLOW_REBASE_MERGE:     computed(schema.TypeBool),
+		REPOSITORY_ALLOW_SQUASH_MERGE:     computed(schema.TypeBool),
+		REPOSITORY_ARCHIVED:               computed(schema.TypeBool),
+		REPOSITORY_CREATED_AT:             computed(schema.TypeString),
+		REPOSITORY_DEFAULT_BRANCH:         computed(schema.TypeString),
+		REPOSITORY_DELETE_BRANCH_ON_MERGE: computed(schema.TypeBool),
+		REPOSITORY_DESCRIPTION:            computed(schema.TypeString),
+		REPOSITORY_DISK_USAGE:             computed(schema.TypeInt),
+		REPOSITORY_FORK:                   computed(schema.TypeBool),
+		REPOSITORY_FULL_NAME:              computed(schema.TypeString),
+		REPOSITORY_HAS_DISCUSSIONS:        computed(schema.TypeBool),
+		REPOSITORY_HAS_ISSUES:             computed(schema.TypeBool),
+		REPOSITORY_HAS_PROJECTS:           computed(schema.TypeBool),
+		REPOSITORY_HAS_WIKI:               computed(schema.TypeBool),
+		REPOSITORY_HOMEPAGE_URL:           computed(schema.TypeString),
+		REPOSITORY_ID:                     computed(schema.TypeString),
+		REPOSITORY_IS_TEMPLATE:            computed(schema.TypeBool),
+		REPOSITORY_LANGUAGES: {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The ten largest languages, by size in bytes.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					REPOSITORY_NAME:          computed(schema.TypeString),
+					REPOSITORY_LANGUAGE_SIZE: computed(schema.TypeInt),
+				},
+			},
+		},
+		REPOSITORY_LICENSE:          computed(schema.TypeString),
+		REPOSITORY_MIRROR:           computed(schema.TypeBool),
+		REPOSITORY_NAME:             computed(schema.TypeString),
+		REPOSITORY_PARENT:           computed(schema.TypeString),
+		REPOSITORY_PARENT_ID:        computed(schema.TypeString),
+		REPOSITORY_PRIMARY_LANGUAGE: computed(schema.TypeString),
+		REPOSITORY_PUSHED_AT:        computed(schema.TypeString),
+		REPOSITORY_TEMPLATE:         computed(schema.TypeString),
+		REPOSITORY_TEMPLATE_ID:      computed(schema.TypeString),
+		REPOSITORY_TOPICS: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		REPOSITORY_VISIBILITY: computed(schema.TypeString),
+	}
+}
+
+func flattenRepositoryDetails(r RepositoryDetails) map[string]interface{} {
+	languages := make([]interface{}, 0, len(r.Languages.Edges))
+	for _, l := range r.Languages.Edges {
+		languages = append(languages, map[string]interface{}{
+			REPOSITORY_NAME:          string(l.Node.Name),
+			REPOSITORY_LANGUAGE_SIZE: int(l.Size),
+		})
+	}
+
+	topics := make([]interface{}, 0, len(r.RepositoryTopics.Nodes))
+	for _, t := range r.RepositoryTopics.Nodes {
+		topics = append(topics, string(t.Topic.Name))
+	}
+
+	parentID := ""
+	if r.Parent.ID != nil {
+		parentID = fmt.Sprintf("%s", r.Parent.ID)
+	}
+	templateID := ""
+	if r.TemplateRepository.ID != nil {
+		templateID = fmt.Sprintf("%s", r.TemplateRepository.ID)
+	}
+
+	return map[string]interface{}{
+		REPOSITORY_ALLOW_AUTO_MERGE:       bool(r.AutoMergeAllowed),
+		REPOSITORY_ALLOW_MERGE_COMMIT:     bool(r.MergeCommitAllowed),
+		REPOSITORY_ALLOW_REBASE_MERGE:     bool(r.RebaseMergeAllowed),
+		REPOSITORY_ALLOW_SQUASH_MERGE:     bool(r.SquashMergeAllowed),
+		REPOSITORY_ARCHIVED:               bool(r.IsArchived),
+		REPOSITORY_CREATED_AT:             formatDateTime(r.CreatedAt),
+		REPOSITORY_DEFAULT_BRANCH:         string(r.DefaultBranchRef.Name),
+		REPOSITORY_DELETE_BRANCH_ON_MERGE: bool(r.DeleteBranchOnMerge),
+		REPOSITORY_DESCRIPTION:            string(r.Description),
+		REPOSITORY_DISK_USAGE:             int(r.DiskUsage),
+		REPOSITORY_FORK:                   bool(r.IsFork),
+		REPOSITORY_FULL_NAME:              string(r.NameWithOwner),
+		REPOSITORY_HAS_DISCUSSIONS:        bool(r.HasDiscussionsEnabled),
+		REPOSITORY_HAS_ISSUES:             bool(r.HasIssuesEnabled),
+		REPOSITORY_HAS_PROJECTS:           bool(r.HasProjectsEnabled),
+		REPOSITORY_HAS_WIKI:               bool(r.HasWikiEnabled),
+		REPOSITORY_HOMEPAGE_URL:           string(r.HomepageURL),
+		REPOSITORY_ID:                     fmt.Sprintf("%s", r.ID),
+		REPOSITORY_IS_TEMPLATE:            bool(r.IsTemplate),
+		REPOSITORY_LANGUAGES:              languages,
+		REPOSITORY_LICENSE:                string(r.LicenseInfo.SpdxID),
+		REPOSITORY_MIRROR:                 bool(r.IsMirror),
+		REPOSITORY_NAME:                   string(r.Name),
+		REPOSITORY_PARENT:                 string(r.Parent.NameWithOwner),
+		REPOSITORY_PARENT_ID:              parentID,
+		REPOSITORY_PRIMARY_LANGUAGE:       string(r.PrimaryLanguage.Name),
+		REPOSITORY_PUSHED_AT:              formatDateTime(r.PushedAt),
+		REPOSITORY_TEMPLATE:               string(r.TemplateRepository.NameWithOwner),
+		REPOSITORY_TEMPLATE_ID:            templateID,
+		REPOSITORY_TOPICS:                 topics,
+		REPOSITORY_VISIBILITY:             string(r.Visibility),
+	}
+}
+
+// getRepositoryDefaultBranch returns the name of the repository's default
+// branch, or "" if the repository is empty.
+func getRepositoryDefaultBranch(ctx context.Context, id string, meta interface{}) (string, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				DefaultBranchRef struct {
+					Name githubv4.String
+				}
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": githubv4.ID(id),
+	}
+
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", err
+	}
+
+	return string(query.Node.Repository.DefaultBranchRef.Name), nil
+}
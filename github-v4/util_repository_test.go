@@ -0,0 +1,34 @@
+package github
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform/helper/schema"
+	"testing"
+)
+
+func TestFlattenRepositoryDetailsMatchesSchema(t *testing.T) {
+	var r RepositoryDetails
+	err := json.Unmarshal([]byte(`{
+		"Name": "example",
+		"Languages": {"Edges": [{"Node": {"Name": "Go"}, "Size": 1024}]},
+		"RepositoryTopics": {"Nodes": [{"Topic": {"Name": "terraform"}}]}
+	}`), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details := flattenRepositoryDetails(r)
+
+	s := repositoryDetailsSchema()
+	for k := range details {
+		if _, ok := s[k]; !ok {
+			t.Errorf("%s is flattened but not declared in repositoryDetailsSchema", k)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceGithubRepositories().Schema, map[string]interface{}{})
+	err = d.Set(ORGANIZATION_REPOSITORIES, []interface{}{details})
+	if err != nil {
+		t.Fatalf("error setting %s: %s", ORGANIZATION_REPOSITORIES, err)
+	}
+}
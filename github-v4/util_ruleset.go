@@ -0,0 +1,727 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	RULESET_BYPASS_ACTOR       = "bypass_actor"
+	RULESET_BYPASS_MODE        = "bypass_mode"
+	RULESET_CONDITIONS         = "conditions"
+	RULESET_DEPLOY_KEY         = "deploy_key"
+	RULESET_ENFORCEMENT        = "enforcement"
+	RULESET_EXCLUDE            = "exclude"
+	RULESET_INCLUDE            = "include"
+	RULESET_NAME               = "name"
+	RULESET_ORGANIZATION_ADMIN = "organization_admin"
+	RULESET_REF_NAME           = "ref_name"
+	RULESET_REPOSITORY_ROLE_ID = "repository_role_id"
+	RULESET_RULES              = "rules"
+	RULESET_TARGET             = "target"
+
+	RULE_BRANCH_NAME_PATTERN                  = "branch_name_pattern"
+	RULE_COMMIT_AUTHOR_EMAIL_PATTERN          = "commit_author_email_pattern"
+	RULE_COMMIT_MESSAGE_PATTERN               = "commit_message_pattern"
+	RULE_COMMITTER_EMAIL_PATTERN              = "committer_email_pattern"
+	RULE_CREATION                             = "creation"
+	RULE_DELETION                             = "deletion"
+	RULE_DISMISS_STALE_REVIEWS_ON_PUSH        = "dismiss_stale_reviews_on_push"
+	RULE_INTEGRATION_ID                       = "integration_id"
+	RULE_NON_FAST_FORWARD                     = "non_fast_forward"
+	RULE_PATTERN                              = "pattern"
+	RULE_PATTERN_NAME                         = "name"
+	RULE_PATTERN_NEGATE                       = "negate"
+	RULE_PATTERN_OPERATOR                     = "operator"
+	RULE_PULL_REQUEST                         = "pull_request"
+	RULE_REQUIRE_CODE_OWNER_REVIEW            = "require_code_owner_review"
+	RULE_REQUIRED_CHECK                       = "required_check"
+	RULE_REQUIRED_DEPLOYMENT_ENVIRONMENTS     = "required_deployment_environments"
+	RULE_REQUIRED_DEPLOYMENTS                 = "required_deployments"
+	RULE_REQUIRED_LINEAR_HISTORY              = "required_linear_history"
+	RULE_REQUIRED_REVIEW_THREAD_RESOLUTION    = "required_review_thread_resolution"
+	RULE_REQUIRED_SIGNATURES                  = "required_signatures"
+	RULE_REQUIRED_STATUS_CHECKS               = "required_status_checks"
+	RULE_STRICT_REQUIRED_STATUS_CHECKS_POLICY = "strict_required_status_checks_policy"
+	RULE_TAG_NAME_PATTERN                     = "tag_name_pattern"
+	RULE_UPDATE                               = "update"
+	RULE_UPDATE_ALLOWS_FETCH_AND_MERGE        = "update_allows_fetch_and_merge"
+)
+
+// The pattern rules share one set of parameters; each is keyed by its
+// RepositoryRuleType.
+var rulePatternTypes = map[string]string{
+	RULE_BRANCH_NAME_PATTERN:         "BRANCH_NAME_PATTERN",
+	RULE_COMMIT_AUTHOR_EMAIL_PATTERN: "COMMIT_AUTHOR_EMAIL_PATTERN",
+	RULE_COMMIT_MESSAGE_PATTERN:      "COMMIT_MESSAGE_PATTERN",
+	RULE_COMMITTER_EMAIL_PATTERN:     "COMMITTER_EMAIL_PATTERN",
+	RULE_TAG_NAME_PATTERN:            "TAG_NAME_PATTERN",
+}
+
+// Rules which take no parameters are toggled by a boolean.
+var ruleToggleTypes = map[string]string{
+	RULE_CREATION:                "CREATION",
+	RULE_DELETION:                "DELETION",
+	RULE_NON_FAST_FORWARD:        "NON_FAST_FORWARD",
+	RULE_REQUIRED_LINEAR_HISTORY: "REQUIRED_LINEAR_HISTORY",
+	RULE_REQUIRED_SIGNATURES:     "REQUIRED_SIGNATURES",
+}
+
+type RulePatternParameters struct {
+	Name     githubv4.String
+	Negate   githubv4.Boolean
+	Operator githubv4.String
+	Pattern  githubv4.String
+}
+
+// RepositoryRule decodes every parameters fragment from the same JSON object,
+// so Type decides which of them is meaningful.
+type RepositoryRule struct {
+	Type       githubv4.String
+	Parameters struct {
+		BranchNamePattern        RulePatternParameters `graphql:"... on BranchNamePatternParameters"`
+		CommitAuthorEmailPattern RulePatternParameters `graphql:"... on CommitAuthorEmailPatternParameters"`
+		CommitMessagePattern     RulePatternParameters `graphql:"... on CommitMessagePatternParameters"`
+		CommitterEmailPattern    RulePatternParameters `graphql:"... on CommitterEmailPatternParameters"`
+		PullRequest              struct {
+			DismissStaleReviewsOnPush      githubv4.Boolean
+			RequireCodeOwnerReview         githubv4.Boolean
+			RequireLastPushApproval        githubv4.Boolean
+			RequiredApprovingReviewCount   githubv4.Int
+			RequiredReviewThreadResolution githubv4.Boolean
+		} `graphql:"... on PullRequestParameters"`
+		RequiredDeployments struct {
+			RequiredDeploymentEnvironments []githubv4.String
+		} `graphql:"... on RequiredDeploymentsParameters"`
+		RequiredStatusChecks struct {
+			RequiredStatusChecks []struct {
+				Context       githubv4.String
+				IntegrationID githubv4.Int
+			}
+			StrictRequiredStatusChecksPolicy githubv4.Boolean
+		} `graphql:"... on RequiredStatusChecksParameters"`
+		TagNamePattern RulePatternParameters `graphql:"... on TagNamePatternParameters"`
+		Update         struct {
+			UpdateAllowsFetchAndMerge githubv4.Boolean
+		} `graphql:"... on UpdateParameters"`
+	}
+}
+
+// RulesetBypassActor is an App, a Team, a repository role, the organization
+// admins or deploy keys; only the first two are reported as an actor.
+type RulesetBypassActor struct {
+	Actor struct {
+		Typename githubv4.String `graphql:"__typename"`
+		App      struct {
+			ID   githubv4.ID
+			Slug githubv4.String
+		} `graphql:"... on App"`
+		Team struct {
+			ID   githubv4.ID
+			Slug githubv4.String
+		} `graphql:"... on Team"`
+	}
+	BypassMode               githubv4.String
+	DeployKey                githubv4.Boolean
+	OrganizationAdmin        githubv4.Boolean
+	RepositoryRoleDatabaseID githubv4.Int
+}
+
+type RepositoryRuleset struct {
+	BypassActors struct {
+		Nodes    []RulesetBypassActor
+		PageInfo PageInfo
+	} `graphql:"bypassActors(first: $bypassActorsFirst, after: $bypassActorsCursor)"`
+	Conditions struct {
+		RefName struct {
+			Exclude []githubv4.String
+			Include []githubv4.String
+		}
+	}
+	Enforcement githubv4.String
+	ID          githubv4.ID
+	Name        githubv4.String
+	// A ruleset has no more rules than there are rule types.
+	Rules struct {
+		Nodes []RepositoryRule
+	} `graphql:"rules(first: 100)"`
+	Source struct {
+		Repository struct {
+			ID githubv4.ID
+		} `graphql:"... on Repository"`
+	}
+	Target githubv4.String
+}
+
+// The vendored githubv4 predates repository rulesets, so the mutation inputs
+// are declared here.
+type CreateRepositoryRulesetInput struct {
+	SourceID githubv4.ID `json:"sourceId"`
+	repositoryRulesetInput
+}
+
+type UpdateRepositoryRulesetInput struct {
+	RepositoryRulesetID githubv4.ID `json:"repositoryRulesetId"`
+	repositoryRulesetInput
+}
+
+type DeleteRepositoryRulesetInput struct {
+	RepositoryRulesetID githubv4.ID `json:"repositoryRulesetId"`
+}
+
+type repositoryRulesetInput struct {
+	BypassActors []RepositoryRulesetBypassActorInput `json:"bypassActors"`
+	Conditions   RepositoryRuleConditionsInput       `json:"conditions"`
+	Enforcement  githubv4.String                     `json:"enforcement"`
+	Name         githubv4.String                     `json:"name"`
+	Rules        []RepositoryRuleInput               `json:"rules"`
+	Target       githubv4.String                     `json:"target"`
+}
+
+type RepositoryRulesetBypassActorInput struct {
+	ActorID                  githubv4.ID       `json:"actorId,omitempty"`
+	BypassMode               githubv4.String   `json:"bypassMode"`
+	DeployKey                *githubv4.Boolean `json:"deployKey,omitempty"`
+	OrganizationAdmin        *githubv4.Boolean `json:"organizationAdmin,omitempty"`
+	RepositoryRoleDatabaseID *githubv4.Int     `json:"repositoryRoleDatabaseId,omitempty"`
+}
+
+type RepositoryRuleConditionsInput struct {
+	RefName RefNameConditionTargetInput `json:"refName"`
+}
+
+type RefNameConditionTargetInput struct {
+	Exclude []githubv4.String `json:"exclude"`
+	Include []githubv4.String `json:"include"`
+}
+
+type RepositoryRuleInput struct {
+	Parameters *RuleParametersInput `json:"parameters,omitempty"`
+	Type       githubv4.String      `json:"type"`
+}
+
+type RuleParametersInput struct {
+	BranchNamePattern        *RulePatternParametersInput          `json:"branchNamePattern,omitempty"`
+	CommitAuthorEmailPattern *RulePatternParametersInput          `json:"commitAuthorEmailPattern,omitempty"`
+	CommitMessagePattern     *RulePatternParametersInput          `json:"commitMessagePattern,omitempty"`
+	CommitterEmailPattern    *RulePatternParametersInput          `json:"committerEmailPattern,omitempty"`
+	PullRequest              *PullRequestParametersInput          `json:"pullRequest,omitempty"`
+	RequiredDeployments      *RequiredDeploymentsParametersInput  `json:"requiredDeployments,omitempty"`
+	RequiredStatusChecks     *RequiredStatusChecksParametersInput `json:"requiredStatusChecks,omitempty"`
+	TagNamePattern           *RulePatternParametersInput          `json:"tagNamePattern,omitempty"`
+	Update                   *UpdateParametersInput               `json:"update,omitempty"`
+}
+
+type RulePatternParametersInput struct {
+	Name     *githubv4.String `json:"name,omitempty"`
+	Negate   githubv4.Boolean `json:"negate"`
+	Operator githubv4.String  `json:"operator"`
+	Pattern  githubv4.String  `json:"pattern"`
+}
+
+type PullRequestParametersInput struct {
+	DismissStaleReviewsOnPush      githubv4.Boolean `json:"dismissStaleReviewsOnPush"`
+	RequireCodeOwnerReview         githubv4.Boolean `json:"requireCodeOwnerReview"`
+	RequireLastPushApproval        githubv4.Boolean `json:"requireLastPushApproval"`
+	RequiredApprovingReviewCount   githubv4.Int     `json:"requiredApprovingReviewCount"`
+	RequiredReviewThreadResolution githubv4.Boolean `json:"requiredReviewThreadResolution"`
+}
+
+type RequiredDeploymentsParametersInput struct {
+	RequiredDeploymentEnvironments []githubv4.String `json:"requiredDeploymentEnvironments"`
+}
+
+type RequiredStatusChecksParametersInput struct {
+	RequiredStatusChecks             []StatusCheckConfigurationInput `json:"requiredStatusChecks"`
+	StrictRequiredStatusChecksPolicy githubv4.Boolean                `json:"strictRequiredStatusChecksPolicy"`
+}
+
+type StatusCheckConfigurationInput struct {
+	Context       githubv4.String `json:"context"`
+	IntegrationID *githubv4.Int   `json:"integrationId,omitempty"`
+}
+
+type UpdateParametersInput struct {
+	UpdateAllowsFetchAndMerge githubv4.Boolean `json:"updateAllowsFetchAndMerge"`
+}
+
+func newRepositoryRulesetInput(d *schema.ResourceData, meta interface{}) (repositoryRulesetInput, error) {
+	input := repositoryRulesetInput{
+		Enforcement: githubv4.String(d.Get(RULESET_ENFORCEMENT).(string)),
+		Name:        githubv4.String(d.Get(RULESET_NAME).(string)),
+		Target:      githubv4.String(d.Get(RULESET_TARGET).(string)),
+	}
+
+	input.Conditions = expandRulesetConditions(d.Get(RULESET_CONDITIONS).([]interface{}))
+	rules, err := expandRulesetRules(d.Get(RULESET_RULES).([]interface{}))
+	if err != nil {
+		return repositoryRulesetInput{}, err
+	}
+	input.Rules = rules
+
+	bypassActors, err := expandRulesetBypassActors(d.Get(RULESET_BYPASS_ACTOR).(*schema.Set).List(), meta)
+	if err != nil {
+		return repositoryRulesetInput{}, err
+	}
+	input.BypassActors = bypassActors
+
+	return input, nil
+}
+
+func expandRulesetConditions(vL []interface{}) RepositoryRuleConditionsInput {
+	conditions := RepositoryRuleConditionsInput{
+		RefName: RefNameConditionTargetInput{
+			Exclude: make([]githubv4.String, 0),
+			Include: make([]githubv4.String, 0),
+		},
+	}
+	m := firstBlock(vL)
+	if m == nil {
+		return conditions
+	}
+	refName := firstBlock(m[RULESET_REF_NAME].([]interface{}))
+	if refName == nil {
+		return conditions
+	}
+
+	for _, v := range refName[RULESET_EXCLUDE].([]interface{}) {
+		conditions.RefName.Exclude = append(conditions.RefName.Exclude, githubv4.String(v.(string)))
+	}
+	for _, v := range refName[RULESET_INCLUDE].([]interface{}) {
+		conditions.RefName.Include = append(conditions.RefName.Include, githubv4.String(v.(string)))
+	}
+
+	return conditions
+}
+
+func expandRulesetRules(vL []interface{}) ([]RepositoryRuleInput, error) {
+	rules := make([]RepositoryRuleInput, 0)
+	m := firstBlock(vL)
+	if m == nil {
+		return rules, nil
+	}
+
+	// The flag is only read back alongside the update rule, so on its own it
+	// would never match state.
+	if m[RULE_UPDATE_ALLOWS_FETCH_AND_MERGE].(bool) && !m[RULE_UPDATE].(bool) {
+		return nil, fmt.Errorf("error %s requires %s to be enabled", RULE_UPDATE_ALLOWS_FETCH_AND_MERGE, RULE_UPDATE)
+	}
+
+	for key, ruleType := range ruleToggleTypes {
+		if m[key].(bool) {
+			rules = append(rules, RepositoryRuleInput{Type: githubv4.String(ruleType)})
+		}
+	}
+
+	if m[RULE_UPDATE].(bool) {
+		rules = append(rules, RepositoryRuleInput{
+			Type: "UPDATE",
+			Parameters: &RuleParametersInput{
+				Update: &UpdateParametersInput{
+					UpdateAllowsFetchAndMerge: githubv4.Boolean(m[RULE_UPDATE_ALLOWS_FETCH_AND_MERGE].(bool)),
+				},
+			},
+		})
+	}
+
+	if p := firstBlock(m[RULE_PULL_REQUEST].([]interface{})); p != nil {
+		rules = append(rules, RepositoryRuleInput{
+			Type: "PULL_REQUEST",
+			Parameters: &RuleParametersInput{
+				PullRequest: &PullRequestParametersInput{
+					DismissStaleReviewsOnPush:      githubv4.Boolean(p[RULE_DISMISS_STALE_REVIEWS_ON_PUSH].(bool)),
+					RequireCodeOwnerReview:         githubv4.Boolean(p[RULE_REQUIRE_CODE_OWNER_REVIEW].(bool)),
+					RequireLastPushApproval:        githubv4.Boolean(p[PROTECTION_REQUIRE_LAST_PUSH_APPROVAL].(bool)),
+					RequiredApprovingReviewCount:   githubv4.Int(p[PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT].(int)),
+					RequiredReviewThreadResolution: githubv4.Boolean(p[RULE_REQUIRED_REVIEW_THREAD_RESOLUTION].(bool)),
+				},
+			},
+		})
+	}
+
+	if p := firstBlock(m[RULE_REQUIRED_DEPLOYMENTS].([]interface{})); p != nil {
+		environments := make([]githubv4.String, 0)
+		for _, v := range p[RULE_REQUIRED_DEPLOYMENT_ENVIRONMENTS].([]interface{}) {
+			environments = append(environments, githubv4.String(v.(string)))
+		}
+		rules = append(rules, RepositoryRuleInput{
+			Type: "REQUIRED_DEPLOYMENTS",
+			Parameters: &RuleParametersInput{
+				RequiredDeployments: &RequiredDeploymentsParametersInput{
+					RequiredDeploymentEnvironments: environments,
+				},
+			},
+		})
+	}
+
+	if p := firstBlock(m[RULE_REQUIRED_STATUS_CHECKS].([]interface{})); p != nil {
+		checks := make([]StatusCheckConfigurationInput, 0)
+		for _, v := range p[RULE_REQUIRED_CHECK].(*schema.Set).List() {
+			c := v.(map[string]interface{})
+			check := StatusCheckConfigurationInput{
+				Context: githubv4.String(c[PROTECTION_STATUS_CHECK_CONTEXT].(string)),
+			}
+			if id := c[RULE_INTEGRATION_ID].(int); id != 0 {
+				check.IntegrationID = githubv4.NewInt(githubv4.Int(id))
+			}
+			checks = append(checks, check)
+		}
+		rules = append(rules, RepositoryRuleInput{
+			Type: "REQUIRED_STATUS_CHECKS",
+			Parameters: &RuleParametersInput{
+				RequiredStatusChecks: &RequiredStatusChecksParametersInput{
+					RequiredStatusChecks:             checks,
+					StrictRequiredStatusChecksPolicy: githubv4.Boolean(p[RULE_STRICT_REQUIRED_STATUS_CHECKS_POLICY].(bool)),
+				},
+			},
+		})
+	}
+
+	for key, ruleType := range rulePatternTypes {
+		p := firstBlock(m[key].([]interface{}))
+		if p == nil {
+			continue
+		}
+
+		parameters := &RulePatternParametersInput{
+			Negate:   githubv4.Boolean(p[RULE_PATTERN_NEGATE].(bool)),
+			Operator: githubv4.String(p[RULE_PATTERN_OPERATOR].(string)),
+			Pattern:  githubv4.String(p[RULE_PATTERN].(string)),
+		}
+		if name := p[RULE_PATTERN_NAME].(string); name != "" {
+			parameters.Name = githubv4.NewString(githubv4.String(name))
+		}
+
+		rule := RepositoryRuleInput{
+			Type:       githubv4.String(ruleType),
+			Parameters: &RuleParametersInput{},
+		}
+		switch key {
+		case RULE_BRANCH_NAME_PATTERN:
+			rule.Parameters.BranchNamePattern = parameters
+		case RULE_COMMIT_AUTHOR_EMAIL_PATTERN:
+			rule.Parameters.CommitAuthorEmailPattern = parameters
+		case RULE_COMMIT_MESSAGE_PATTERN:
+			rule.Parameters.CommitMessagePattern = parameters
+		case RULE_COMMITTER_EMAIL_PATTERN:
+			rule.Parameters.CommitterEmailPattern = parameters
+		case RULE_TAG_NAME_PATTERN:
+			rule.Parameters.TagNamePattern = parameters
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// expandRulesetBypassActors resolves App and team slugs to the node IDs the
+// ruleset mutations expect. Each block names exactly one kind of actor.
+func expandRulesetBypassActors(vL []interface{}, meta interface{}) ([]RepositoryRulesetBypassActorInput, error) {
+	actors := make([]RepositoryRulesetBypassActorInput, 0, len(vL))
+	for _, v := range vL {
+		m := v.(map[string]interface{})
+		actor := RepositoryRulesetBypassActorInput{
+			BypassMode: githubv4.String(m[RULESET_BYPASS_MODE].(string)),
+		}
+
+		count := 0
+		if app := m[ACTOR_APP].(string); app != "" {
+			id, err := getAppID(app, meta)
+			if err != nil {
+				return nil, err
+			}
+			actor.ActorID = id
+			count++
+		}
+		if team := m[ACTOR_TEAM].(string); team != "" {
+			id, err := getTeamID(team, meta)
+			if err != nil {
+				return nil, err
+			}
+			actor.ActorID = id
+			count++
+		}
+		if role := m[RULESET_REPOSITORY_ROLE_ID].(int); role != 0 {
+			actor.RepositoryRoleDatabaseID = githubv4.NewInt(githubv4.Int(role))
+			count++
+		}
+		if m[RULESET_ORGANIZATION_ADMIN].(bool) {
+			actor.OrganizationAdmin = githubv4.NewBoolean(true)
+			count++
+		}
+		if m[RULESET_DEPLOY_KEY].(bool) {
+			actor.DeployKey = githubv4.NewBoolean(true)
+			count++
+		}
+		if count != 1 {
+			return nil, fmt.Errorf("error exactly one of %s, %s, %s, %s or %s must be declared per %s",
+				ACTOR_APP, ACTOR_TEAM, RULESET_REPOSITORY_ROLE_ID, RULESET_ORGANIZATION_ADMIN, RULESET_DEPLOY_KEY, RULESET_BYPASS_ACTOR)
+		}
+
+		actors = append(actors, actor)
+	}
+
+	return actors, nil
+}
+
+func flattenRulesetConditions(ruleset RepositoryRuleset) []interface{} {
+	exclude := make([]interface{}, 0, len(ruleset.Conditions.RefName.Exclude))
+	for _, v := range ruleset.Conditions.RefName.Exclude {
+		exclude = append(exclude, string(v))
+	}
+	include := make([]interface{}, 0, len(ruleset.Conditions.RefName.Include))
+	for _, v := range ruleset.Conditions.RefName.Include {
+		include = append(include, string(v))
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			RULESET_REF_NAME: []interface{}{
+				map[string]interface{}{
+					RULESET_EXCLUDE: exclude,
+					RULESET_INCLUDE: include,
+				},
+			},
+		},
+	}
+}
+
+// checkRulesetRules fails if the ruleset has a rule the schema cannot
+// represent. Such a rule would be left out of state and then dropped by the
+// next update, which replaces every rule.
+func checkRulesetRules(ruleset RepositoryRuleset) error {
+	for _, r := range ruleset.Rules.Nodes {
+		switch r.Type {
+		case "UPDATE", "PULL_REQUEST", "REQUIRED_DEPLOYMENTS", "REQUIRED_STATUS_CHECKS":
+			continue
+		}
+
+		supported := false
+		for _, ruleType := range ruleToggleTypes {
+			supported = supported || string(r.Type) == ruleType
+		}
+		for _, ruleType := range rulePatternTypes {
+			supported = supported || string(r.Type) == ruleType
+		}
+		if !supported {
+			return fmt.Errorf("error ruleset %s has a %s rule, which is not supported; remove it in GitHub before managing the ruleset here", ruleset.Name, r.Type)
+		}
+	}
+
+	return nil
+}
+
+func flattenRulesetRules(ruleset RepositoryRuleset) []interface{} {
+	m := map[string]interface{}{
+		RULE_UPDATE:                        false,
+		RULE_UPDATE_ALLOWS_FETCH_AND_MERGE: false,
+	}
+	for key := range ruleToggleTypes {
+		m[key] = false
+	}
+
+	for _, r := range ruleset.Rules.Nodes {
+		parameters := r.Parameters
+
+		var pattern *RulePatternParameters
+		switch r.Type {
+		case "UPDATE":
+			m[RULE_UPDATE] = true
+			m[RULE_UPDATE_ALLOWS_FETCH_AND_MERGE] = bool(parameters.Update.UpdateAllowsFetchAndMerge)
+		case "PULL_REQUEST":
+			m[RULE_PULL_REQUEST] = []interface{}{
+				map[string]interface{}{
+					RULE_DISMISS_STALE_REVIEWS_ON_PUSH:         bool(parameters.PullRequest.DismissStaleReviewsOnPush),
+					RULE_REQUIRE_CODE_OWNER_REVIEW:             bool(parameters.PullRequest.RequireCodeOwnerReview),
+					PROTECTION_REQUIRE_LAST_PUSH_APPROVAL:      bool(parameters.PullRequest.RequireLastPushApproval),
+					PROTECTION_REQUIRED_APPROVING_REVIEW_COUNT: int(parameters.PullRequest.RequiredApprovingReviewCount),
+					RULE_REQUIRED_REVIEW_THREAD_RESOLUTION:     bool(parameters.PullRequest.RequiredReviewThreadResolution),
+				},
+			}
+		case "REQUIRED_DEPLOYMENTS":
+			environments := make([]interface{}, 0)
+			for _, e := range parameters.RequiredDeployments.RequiredDeploymentEnvironments {
+				environments = append(environments, string(e))
+			}
+			m[RULE_REQUIRED_DEPLOYMENTS] = []interface{}{
+				map[string]interface{}{
+					RULE_REQUIRED_DEPLOYMENT_ENVIRONMENTS: environments,
+				},
+			}
+		case "REQUIRED_STATUS_CHECKS":
+			checks := make([]interface{}, 0)
+			for _, c := range parameters.RequiredStatusChecks.RequiredStatusChecks {
+				checks = append(checks, map[string]interface{}{
+					PROTECTION_STATUS_CHECK_CONTEXT: string(c.Context),
+					RULE_INTEGRATION_ID:             int(c.IntegrationID),
+				})
+			}
+			m[RULE_REQUIRED_STATUS_CHECKS] = []interface{}{
+				map[string]interface{}{
+					RULE_REQUIRED_CHECK:                       checks,
+					RULE_STRICT_REQUIRED_STATUS_CHECKS_POLICY: bool(parameters.RequiredStatusChecks.StrictRequiredStatusChecksPolicy),
+				},
+			}
+		case "BRANCH_NAME_PATTERN":
+			pattern = &parameters.BranchNamePattern
+		case "COMMIT_AUTHOR_EMAIL_PATTERN":
+			pattern = &parameters.CommitAuthorEmailPattern
+		case "COMMIT_MESSAGE_PATTERN":
+			pattern = &parameters.CommitMessagePattern
+		case "COMMITTER_EMAIL_PATTERN":
+			pattern = &parameters.CommitterEmailPattern
+		case "TAG_NAME_PATTERN":
+			pattern = &parameters.TagNamePattern
+		default:
+			for key, ruleType := range ruleToggleTypes {
+				if string(r.Type) == ruleType {
+					m[key] = true
+				}
+			}
+		}
+
+		if pattern != nil {
+			for key, ruleType := range rulePatternTypes {
+				if string(r.Type) == ruleType {
+					m[key] = []interface{}{
+						map[string]interface{}{
+							RULE_PATTERN_NAME:     string(pattern.Name),
+							RULE_PATTERN_NEGATE:   bool(pattern.Negate),
+							RULE_PATTERN_OPERATOR: string(pattern.Operator),
+							RULE_PATTERN:          string(pattern.Pattern),
+						},
+					}
+				}
+			}
+		}
+	}
+
+	return []interface{}{m}
+}
+
+func flattenRulesetBypassActors(actors []RulesetBypassActor) []interface{} {
+	out := make([]interface{}, 0, len(actors))
+	for _, a := range actors {
+		m := map[string]interface{}{
+			ACTOR_APP:                  "",
+			ACTOR_ID:                   "",
+			ACTOR_TEAM:                 "",
+			ACTOR_TYPE:                 "",
+			RULESET_BYPASS_MODE:        string(a.BypassMode),
+			RULESET_DEPLOY_KEY:         bool(a.DeployKey),
+			RULESET_ORGANIZATION_ADMIN: bool(a.OrganizationAdmin),
+			RULESET_REPOSITORY_ROLE_ID: int(a.RepositoryRoleDatabaseID),
+		}
+
+		switch {
+		case a.Actor.Typename == "App":
+			m[ACTOR_APP] = string(a.Actor.App.Slug)
+			m[ACTOR_ID] = fmt.Sprintf("%s", a.Actor.App.ID)
+			m[ACTOR_TYPE] = "App"
+		case a.Actor.Typename == "Team":
+			m[ACTOR_TEAM] = string(a.Actor.Team.Slug)
+			m[ACTOR_ID] = fmt.Sprintf("%s", a.Actor.Team.ID)
+			m[ACTOR_TYPE] = "Team"
+		case a.RepositoryRoleDatabaseID != 0:
+			m[ACTOR_TYPE] = "RepositoryRole"
+		case bool(a.OrganizationAdmin):
+			m[ACTOR_TYPE] = "OrganizationAdmin"
+		case bool(a.DeployKey):
+			m[ACTOR_TYPE] = "DeployKey"
+		}
+
+		out = append(out, m)
+	}
+
+	return out
+}
+
+// getRepositoryRuleset reads a ruleset by node ID, paging through its bypass
+// actors.
+func getRepositoryRuleset(ctx context.Context, id string, meta interface{}) (RepositoryRuleset, error) {
+	var query struct {
+		Node struct {
+			Ruleset RepositoryRuleset `graphql:"... on RepositoryRuleset"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":                 githubv4.ID(id),
+		"bypassActorsFirst":  githubv4.Int(100),
+		"bypassActorsCursor": (*githubv4.String)(nil),
+	}
+
+	client := meta.(*Organization).Client
+
+	var bypassActors []RulesetBypassActor
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return RepositoryRuleset{}, err
+		}
+
+		bypassActors = append(bypassActors, query.Node.Ruleset.BypassActors.Nodes...)
+
+		if !query.Node.Ruleset.BypassActors.PageInfo.HasNextPage {
+			break
+		}
+		variables["bypassActorsCursor"] = githubv4.NewString(query.Node.Ruleset.BypassActors.PageInfo.EndCursor)
+	}
+
+	ruleset := query.Node.Ruleset
+	ruleset.BypassActors.Nodes = bypassActors
+
+	return ruleset, nil
+}
+
+// getRepositoryRulesetID finds a ruleset of a repository by name, returning
+// nil if there is none.
+func getRepositoryRulesetID(ctx context.Context, repositoryID githubv4.ID, name string, meta interface{}) (githubv4.ID, error) {
+	var query struct {
+		Node struct {
+			Repository struct {
+				Rulesets struct {
+					Nodes []struct {
+						ID   githubv4.ID
+						Name githubv4.String
+					}
+					PageInfo PageInfo
+				} `graphql:"rulesets(first: $first, after: $cursor)"`
+			} `graphql:"... on Repository"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":     repositoryID,
+		"first":  githubv4.Int(100),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	client := meta.(*Organization).Client
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range query.Node.Repository.Rulesets.Nodes {
+			if string(r.Name) == name {
+				return r.ID, nil
+			}
+		}
+
+		if !query.Node.Repository.Rulesets.PageInfo.HasNextPage {
+			return nil, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Node.Repository.Rulesets.PageInfo.EndCursor)
+	}
+}
+
+// firstBlock returns the single element of a MaxItems: 1 block, or nil if it
+// is absent or empty.
+func firstBlock(vL []interface{}) map[string]interface{} {
+	if len(vL) == 0 || vL[0] == nil {
+		return nil
+	}
+	return vL[0].(map[string]interface{})
+}
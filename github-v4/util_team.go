@@ -1,16 +1,33 @@
 package github
 
-import "github.com/shurcooL/githubv4"
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/shurcooL/githubv4"
+	"strings"
+)
 
 const (
-	TEAM_CHILD_TEAMS = "child_teams"
-	TEAM_DESCRIPTION = "description"
-	TEAM_ID          = "team_id"
-	TEAM_MEMBERS     = "members"
-	TEAM_NAME        = "name"
-	TEAM_PARENT_TEAM = "parent_team"
-	TEAM_PRIVACY     = "privacy"
-	TEAM_SLUG        = "slug"
+	TEAM_CHILD_TEAMS               = "child_teams"
+	TEAM_DESCRIPTION               = "description"
+	TEAM_ID                        = "team_id"
+	TEAM_MEMBER                    = "member"
+	TEAM_MEMBERS                   = "members"
+	TEAM_MEMBERS_IGNORE_CHILD_TEAM = "ignore_child_team_members"
+	TEAM_NAME                      = "name"
+	TEAM_NOTIFICATION_SETTING      = "notification_setting"
+	TEAM_PARENT_TEAM               = "parent_team"
+	TEAM_PARENT_TEAM_ID            = "parent_team_id"
+	TEAM_PARENT_TEAM_SLUG          = "parent_team_slug"
+	TEAM_PERMISSION                = "permission"
+	TEAM_PRIVACY                   = "privacy"
+	TEAM_REPOSITORIES              = "repositories"
+	TEAM_REVIEW_ALGORITHM          = "algorithm"
+	TEAM_REVIEW_MEMBER_COUNT       = "member_count"
+	TEAM_REVIEW_NOTIFY_TEAM        = "notify_team"
+	TEAM_REVIEW_REQUEST_DELEGATION = "review_request_delegation"
+	TEAM_SLUG                      = "slug"
 )
 
 type Team struct {
@@ -27,13 +44,293 @@ type Team struct {
 			Role githubv4.TeamMemberRole
 		}
 		PageInfo PageInfo
-	} `graphql:"members(first: $membersFirst, after: $membersCursor)"`
+	} `graphql:"members(first: $membersFirst, after: $membersCursor, membership: $membership)"`
 	ParentTeam struct {
 		ID   githubv4.ID
 		Slug githubv4.String
 	}
-	Description githubv4.String
-	ID          githubv4.ID
-	Name        githubv4.String
-	Privacy     githubv4.TeamPrivacy
+	DatabaseID                         githubv4.Int
+	Description                        githubv4.String
+	ID                                 githubv4.ID
+	Name                               githubv4.String
+	NotificationSetting                githubv4.String
+	Privacy                            githubv4.TeamPrivacy
+	ReviewRequestDelegationAlgorithm   githubv4.String
+	ReviewRequestDelegationEnabled     githubv4.Boolean
+	ReviewRequestDelegationMemberCount githubv4.Int
+	ReviewRequestDelegationNotifyTeam  githubv4.Boolean
+	Slug                               githubv4.String
+}
+
+type TeamRepository struct {
+	Node struct {
+		ID   githubv4.ID
+		Name githubv4.String
+	}
+	Permission githubv4.RepositoryPermission
+}
+
+type TeamResourceData struct {
+	Description             string
+	Name                    string
+	NotificationSetting     string
+	ParentTeamID            string
+	ParentTeamSlug          string
+	Privacy                 string
+	ReviewAlgorithm         string
+	ReviewMemberCount       int
+	ReviewNotifyTeam        bool
+	ReviewRequestDelegation bool
+}
+
+// UpdateTeamReviewAssignmentInput is not part of the vendored githubv4.
+type UpdateTeamReviewAssignmentInput struct {
+	ID              githubv4.ID       `json:"id"`
+	Enabled         githubv4.Boolean  `json:"enabled"`
+	Algorithm       *githubv4.String  `json:"algorithm,omitempty"`
+	NotifyTeam      *githubv4.Boolean `json:"notifyTeam,omitempty"`
+	TeamMemberCount *githubv4.Int     `json:"teamMemberCount,omitempty"`
+}
+
+func teamResourceData(d *schema.ResourceData) (TeamResourceData, error) {
+	data := TeamResourceData{}
+
+	data.Name = d.Get(TEAM_NAME).(string)
+	data.Description = d.Get(TEAM_DESCRIPTION).(string)
+	data.Privacy = d.Get(TEAM_PRIVACY).(string)
+	data.NotificationSetting = d.Get(TEAM_NOTIFICATION_SETTING).(string)
+
+	if v, ok := d.GetOk(TEAM_PARENT_TEAM_ID); ok {
+		data.ParentTeamID = v.(string)
+	}
+
+	if v, ok := d.GetOk(TEAM_PARENT_TEAM_SLUG); ok {
+		data.ParentTeamSlug = v.(string)
+	}
+
+	if v, ok := d.GetOk(TEAM_REVIEW_REQUEST_DELEGATION); ok {
+		vL := v.([]interface{})
+		if len(vL) > 1 {
+			return TeamResourceData{},
+				fmt.Errorf("error multiple %s declarations", TEAM_REVIEW_REQUEST_DELEGATION)
+		}
+		for _, v := range vL {
+			if v == nil {
+				break
+			}
+
+			data.ReviewRequestDelegation = true
+
+			m := v.(map[string]interface{})
+			if v, ok := m[TEAM_REVIEW_ALGORITHM]; ok {
+				data.ReviewAlgorithm = v.(string)
+			}
+			if v, ok := m[TEAM_REVIEW_MEMBER_COUNT]; ok {
+				data.ReviewMemberCount = v.(int)
+			}
+			if v, ok := m[TEAM_REVIEW_NOTIFY_TEAM]; ok {
+				data.ReviewNotifyTeam = v.(bool)
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// teamRestPrivacy maps the GraphQL TeamPrivacy enum to the REST equivalent.
+func teamRestPrivacy(privacy string) string {
+	if privacy == string(githubv4.TeamPrivacyVisible) {
+		return "closed"
+	}
+	return strings.ToLower(privacy)
+}
+
+// getTeamDatabaseID resolves a team, given by slug or node ID, to the
+// numeric ID the REST API expects.
+func getTeamDatabaseID(slug string, id string, meta interface{}) (int, error) {
+	if id == "" {
+		teamID, err := getTeamID(slug, meta)
+		if err != nil {
+			return 0, err
+		}
+		id = fmt.Sprintf("%s", teamID)
+	}
+
+	var query struct {
+		Node struct {
+			Team struct {
+				DatabaseID githubv4.Int
+			} `graphql:"... on Team"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": githubv4.ID(id),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(query.Node.Team.DatabaseID), nil
+}
+
+func getTeamID(slug string, meta interface{}) (githubv4.ID, error) {
+	var query struct {
+		Organization struct {
+			Team struct {
+				ID githubv4.ID
+			} `graphql:"team(slug: $slug)"`
+		} `graphql:"organization(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login": githubv4.String(meta.(*Organization).Name),
+		"slug":  githubv4.String(slug),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if query.Organization.Team.ID == nil {
+		return nil, fmt.Errorf("error team %s not found in %s", slug, meta.(*Organization).Name)
+	}
+
+	return query.Organization.Team.ID, nil
+}
+
+type TeamMember struct {
+	Node User
+	Role githubv4.TeamMemberRole
+}
+
+// getTeamMembers pages through the members of a team, returning them along
+// with the team's current slug.
+func getTeamMembers(ctx context.Context, id string, membership githubv4.TeamMembershipType, meta interface{}) (string, []TeamMember, error) {
+	var query struct {
+		Node struct {
+			Team Team `graphql:"... on Team"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":              githubv4.ID(id),
+		"childTeamFirst":  githubv4.Int(1),
+		"childTeamCursor": (*githubv4.String)(nil),
+		"immediateOnly":   githubv4.Boolean(true),
+		"membersFirst":    githubv4.Int(100),
+		"membersCursor":   (*githubv4.String)(nil),
+		"membership":      membership,
+	}
+
+	client := meta.(*Organization).Client
+
+	var allMembers []TeamMember
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return "", nil, err
+		}
+
+		for _, e := range query.Node.Team.Members.Edges {
+			allMembers = append(allMembers, TeamMember{Node: e.Node, Role: e.Role})
+		}
+
+		if !query.Node.Team.Members.PageInfo.HasNextPage {
+			break
+		}
+		variables["membersCursor"] = githubv4.NewString(query.Node.Team.Members.PageInfo.EndCursor)
+	}
+
+	return string(query.Node.Team.Slug), allMembers, nil
+}
+
+// getTeamInvitations returns those invited to join a team who have yet to
+// accept. An invitation only carries the organization role, so the team role
+// comes from each pending membership. Invitations by email have no login and
+// are skipped.
+func getTeamInvitations(ctx context.Context, slug string, meta interface{}) ([]TeamMember, error) {
+	var allInvitations []TeamMember
+	for page := 1; ; page++ {
+		var invitations []OrganizationInvitation
+		path := fmt.Sprintf("/orgs/%s/teams/%s/invitations?per_page=100&page=%d", meta.(*Organization).Name, slug, page)
+		_, err := restRequest(ctx, meta, "GET", path, nil, &invitations)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range invitations {
+			if i.Login == "" {
+				continue
+			}
+
+			var membership struct {
+				Role string `json:"role"`
+			}
+			path := fmt.Sprintf("/orgs/%s/teams/%s/memberships/%s", meta.(*Organization).Name, slug, i.Login)
+			_, err := restRequest(ctx, meta, "GET", path, nil, &membership)
+			if err != nil {
+				return nil, err
+			}
+
+			allInvitations = append(allInvitations, TeamMember{
+				Node: User{Login: githubv4.String(i.Login)},
+				Role: githubv4.TeamMemberRole(strings.ToUpper(membership.Role)),
+			})
+		}
+
+		if len(invitations) < 100 {
+			break
+		}
+	}
+
+	return allInvitations, nil
+}
+
+// getTeamRepositories pages through the repositories a team has been granted
+// access to, returning them along with the team's node ID.
+func getTeamRepositories(slug string, meta interface{}) (githubv4.ID, []TeamRepository, error) {
+	var query struct {
+		Organization struct {
+			Team struct {
+				ID           githubv4.ID
+				Repositories struct {
+					Edges    []TeamRepository
+					PageInfo PageInfo
+				} `graphql:"repositories(first: $first, after: $cursor)"`
+			} `graphql:"team(slug: $slug)"`
+		} `graphql:"organization(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login":  githubv4.String(meta.(*Organization).Name),
+		"slug":   githubv4.String(slug),
+		"first":  githubv4.Int(100),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+
+	var allRepositories []TeamRepository
+	for {
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return nil, nil, err
+		}
+		if query.Organization.Team.ID == nil {
+			return nil, nil, fmt.Errorf("error team %s not found in %s", slug, meta.(*Organization).Name)
+		}
+
+		allRepositories = append(allRepositories, query.Organization.Team.Repositories.Edges...)
+
+		if !query.Organization.Team.Repositories.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(query.Organization.Team.Repositories.PageInfo.EndCursor)
+	}
+
+	return query.Organization.Team.ID, allRepositories, nil
 }
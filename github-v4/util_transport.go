@@ -0,0 +1,229 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RATE_LIMIT_INITIAL_BACKOFF = 1 * time.Second
+	RATE_LIMIT_MAX_BACKOFF     = 60 * time.Second
+)
+
+// rateLimitTransport sits beneath the GraphQL client and retries requests
+// which GitHub rejected because of primary or secondary rate limits, abuse
+// detection or a gateway error. Requests rejected by a rate limit were never
+// executed and are always retried; anything else is only retried for queries,
+// as a mutation may have been applied before the failure was returned.
+type rateLimitTransport struct {
+	Base        http.RoundTripper
+	MaxRetries  int
+	PointBudget int
+
+	mu            sync.Mutex
+	spent         int
+	lastRemaining int
+	lastReset     string
+}
+
+type graphQLRequest struct {
+	Query string `json:"query"`
+}
+
+type graphQLErrors struct {
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.checkBudget(); err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+	idempotent := req.Method == http.MethodGet || isGraphQLQuery(body)
+
+	for attempt := 0; ; attempt++ {
+		r := req.Clone(req.Context())
+		if body != nil {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			r.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		res, err := t.Base.RoundTrip(r)
+		if err != nil {
+			if !idempotent || attempt >= t.MaxRetries {
+				return nil, err
+			}
+			log.Printf("[WARN] GraphQL request failed, retrying (%d/%d): %s", attempt+1, t.MaxRetries, err)
+			if err := sleepContext(req, backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		t.recordUsage(res)
+
+		wait, retry, err := t.shouldRetry(res, idempotent, attempt)
+		if err != nil || !retry || attempt >= t.MaxRetries {
+			return res, err
+		}
+		res.Body.Close()
+
+		log.Printf("[WARN] GraphQL request limited (%s), retrying in %s (%d/%d)", res.Status, wait, attempt+1, t.MaxRetries)
+		if err := sleepContext(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry inspects a response and reports whether, and after how long,
+// the request should be sent again. The response body is restored so the
+// caller can still decode it.
+func (t *rateLimitTransport) shouldRetry(res *http.Response, idempotent bool, attempt int) (time.Duration, bool, error) {
+	switch res.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if wait, ok := rateLimitWait(res.Header); ok {
+			return wait, true, nil
+		}
+		return 0, false, nil
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return backoff(attempt), idempotent, nil
+	case http.StatusOK:
+	default:
+		return 0, false, nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return 0, false, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	var out graphQLErrors
+	if err := json.Unmarshal(b, &out); err != nil {
+		return 0, false, nil
+	}
+	for _, e := range out.Errors {
+		if e.Type == "RATE_LIMITED" {
+			wait, ok := rateLimitWait(res.Header)
+			if !ok {
+				wait = backoff(attempt)
+			}
+			return wait, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// recordUsage tracks the GraphQL points consumed during this run from the
+// X-RateLimit-Remaining header. Usage is measured within a single rate limit
+// window; a new window becomes the baseline for subsequent requests.
+func (t *rateLimitTransport) recordUsage(res *http.Response) {
+	remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	if resource := res.Header.Get("X-RateLimit-Resource"); resource != "" && resource != "graphql" {
+		return
+	}
+	reset := res.Header.Get("X-RateLimit-Reset")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if reset == t.lastReset && t.lastRemaining > remaining {
+		t.spent += t.lastRemaining - remaining
+	}
+	t.lastRemaining = remaining
+	t.lastReset = reset
+}
+
+func (t *rateLimitTransport) checkBudget() error {
+	if t.PointBudget <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.spent >= t.PointBudget {
+		return fmt.Errorf("error: GraphQL point budget of %d exhausted (%d used)", t.PointBudget, t.spent)
+	}
+
+	return nil
+}
+
+// rateLimitWait returns how long GitHub has asked us to wait, either through
+// Retry-After (secondary limits and abuse detection) or, once the primary
+// limit is exhausted, until X-RateLimit-Reset.
+func rateLimitWait(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if s, err := strconv.Atoi(v); err == nil {
+			return time.Duration(s) * time.Second, true
+		}
+	}
+
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Until(time.Unix(v, 0))
+			if wait < 0 {
+				wait = 0
+			}
+			return wait + time.Second, true
+		}
+	}
+
+	return 0, false
+}
+
+func backoff(attempt int) time.Duration {
+	wait := RATE_LIMIT_INITIAL_BACKOFF << uint(attempt)
+	if wait <= 0 || wait > RATE_LIMIT_MAX_BACKOFF {
+		return RATE_LIMIT_MAX_BACKOFF
+	}
+	return wait
+}
+
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isGraphQLQuery(body []byte) bool {
+	var r graphQLRequest
+	if err := json.Unmarshal(body, &r); err != nil {
+		return false
+	}
+	return !strings.HasPrefix(strings.TrimSpace(r.Query), "mutation")
+}
@@ -48,3 +48,23 @@ func usersResourceData(d *schema.ResourceData) (UsersResourceData, error) {
 
 	return data, nil
 }
+
+func getUserID(login string, meta interface{}) (githubv4.ID, error) {
+	var query struct {
+		User struct {
+			ID githubv4.ID
+		} `graphql:"user(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login": githubv4.String(login),
+	}
+
+	ctx := meta.(*Organization).StopContext
+	client := meta.(*Organization).Client
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.User.ID, nil
+}